@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	metaerror "meta/meta-error"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	K   string `json:"k"`
+}
+
+// LoadJwksFile 从 JWKS 文件中读取校验密钥，支持 RSA、Ed25519 与 oct 类型
+func LoadJwksFile(path string) ([]*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "read jwks file failed: %s", path)
+	}
+	return ParseJwks(data)
+}
+
+func ParseJwks(data []byte) ([]*Key, error) {
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, metaerror.Wrap(err, "unmarshal jwks failed")
+	}
+	var keys []*Key
+	for _, item := range jwks.Keys {
+		if item.Use != "" && item.Use != "sig" {
+			continue
+		}
+		key, err := parseJwk(&item)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parseJwk(item *jwk) (*Key, error) {
+	switch item.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(item.N)
+		if err != nil {
+			return nil, metaerror.Wrap(err, "decode jwk n failed, kid:%s", item.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(item.E)
+		if err != nil {
+			return nil, metaerror.Wrap(err, "decode jwk e failed, kid:%s", item.Kid)
+		}
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		key := NewRsaPublicKey(item.Kid, publicKey)
+		if method := jwt.GetSigningMethod(item.Alg); method != nil {
+			key.Method = method
+		}
+		return key, nil
+	case "OKP":
+		if item.Crv != "Ed25519" {
+			return nil, metaerror.New("unsupported jwk curve: %s, kid:%s", item.Crv, item.Kid)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(item.X)
+		if err != nil {
+			return nil, metaerror.Wrap(err, "decode jwk x failed, kid:%s", item.Kid)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, metaerror.New("invalid ed25519 key size: %d, kid:%s", len(x), item.Kid)
+		}
+		return NewEdDsaPublicKey(item.Kid, x), nil
+	case "oct":
+		k, err := base64.RawURLEncoding.DecodeString(item.K)
+		if err != nil {
+			return nil, metaerror.Wrap(err, "decode jwk k failed, kid:%s", item.Kid)
+		}
+		key := NewHmacKey(item.Kid, k)
+		// JWKS 中的对称密钥仅用于校验
+		key.SignKey = nil
+		if method := jwt.GetSigningMethod(item.Alg); method != nil {
+			key.Method = method
+		}
+		return key, nil
+	default:
+		return nil, metaerror.New("unsupported jwk type: %s, kid:%s", item.Kty, item.Kid)
+	}
+}
@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	metaerror "meta/meta-error"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key 用于签名或校验的密钥，SignKey 为空时只能用于校验
+type Key struct {
+	Id        string
+	Method    jwt.SigningMethod
+	SignKey   any
+	VerifyKey any
+}
+
+func NewHmacKey(id string, secret []byte) *Key {
+	return &Key{Id: id, Method: jwt.SigningMethodHS256, SignKey: secret, VerifyKey: secret}
+}
+
+func NewRsaKey(id string, privateKey *rsa.PrivateKey) *Key {
+	return &Key{Id: id, Method: jwt.SigningMethodRS256, SignKey: privateKey, VerifyKey: &privateKey.PublicKey}
+}
+
+func NewRsaPublicKey(id string, publicKey *rsa.PublicKey) *Key {
+	return &Key{Id: id, Method: jwt.SigningMethodRS256, VerifyKey: publicKey}
+}
+
+func NewEdDsaKey(id string, privateKey ed25519.PrivateKey) *Key {
+	return &Key{Id: id, Method: jwt.SigningMethodEdDSA, SignKey: privateKey, VerifyKey: privateKey.Public()}
+}
+
+func NewEdDsaPublicKey(id string, publicKey ed25519.PublicKey) *Key {
+	return &Key{Id: id, Method: jwt.SigningMethodEdDSA, VerifyKey: publicKey}
+}
+
+// KeySet 按 kid 管理多把密钥，支持轮换
+// 签发使用当前签名密钥，校验按 token 头中的 kid 选择密钥
+type KeySet struct {
+	mutex     sync.RWMutex
+	keys      map[string]*Key
+	signKeyId string
+}
+
+func NewKeySet(keys ...*Key) *KeySet {
+	keySet := &KeySet{keys: make(map[string]*Key)}
+	for _, key := range keys {
+		keySet.AddKey(key)
+	}
+	return keySet
+}
+
+// AddKey 添加或替换密钥，第一把可签名的密钥默认作为签名密钥
+func (s *KeySet) AddKey(key *Key) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.keys[key.Id] = key
+	if s.signKeyId == "" && key.SignKey != nil {
+		s.signKeyId = key.Id
+	}
+}
+
+func (s *KeySet) RemoveKey(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.keys, id)
+	if s.signKeyId == id {
+		s.signKeyId = ""
+	}
+}
+
+// SetSigningKey 切换签名密钥，旧密钥保留用于校验已签发的 token
+func (s *KeySet) SetSigningKey(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return metaerror.New("key not found: %s", id)
+	}
+	if key.SignKey == nil {
+		return metaerror.New("key can not sign: %s", id)
+	}
+	s.signKeyId = id
+	return nil
+}
+
+// ReplaceVerifyKeys 替换所有仅用于校验的密钥，保留可签名的密钥，用于重新加载 JWKS
+func (s *KeySet) ReplaceVerifyKeys(keys []*Key) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for id, key := range s.keys {
+		if key.SignKey == nil {
+			delete(s.keys, id)
+		}
+	}
+	for _, key := range keys {
+		if _, ok := s.keys[key.Id]; !ok {
+			s.keys[key.Id] = key
+		}
+	}
+}
+
+// Sign 使用当前签名密钥签发 token，并在头中写入 kid
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	s.mutex.RLock()
+	key, ok := s.keys[s.signKeyId]
+	s.mutex.RUnlock()
+	if !ok {
+		return "", metaerror.New("signing key not set")
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Id
+	return token.SignedString(key.SignKey)
+}
+
+// Keyfunc 按 kid 选择校验密钥，并要求签名算法与密钥一致，防止算法混淆
+// token 未携带 kid 且仅有一把密钥时使用该密钥
+func (s *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var key *Key
+	if kid, ok := token.Header["kid"].(string); ok {
+		key = s.keys[kid]
+	} else if len(s.keys) == 1 {
+		for _, onlyKey := range s.keys {
+			key = onlyKey
+		}
+	}
+	if key == nil {
+		return nil, metaerror.New("unknown key id: %v", token.Header["kid"])
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, metaerror.New("unexpected signing method: %s", token.Method.Alg())
+	}
+	return key.VerifyKey, nil
+}
+
+// GetMethods 返回所有密钥的签名算法，用于限制解析时允许的算法
+func (s *KeySet) GetMethods() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var methods []string
+	seen := make(map[string]bool)
+	for _, key := range s.keys {
+		alg := key.Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// Validate 使用密钥集校验 token 并解析到 claims
+func (s *KeySet) Validate(token string, claims jwt.Claims, options ...jwt.ParserOption) error {
+	return s.ValidateContext(context.Background(), token, claims, options...)
+}
+
+// ValidateContext 同 Validate，ctx 用于吊销检查
+func (s *KeySet) ValidateContext(ctx context.Context, token string, claims jwt.Claims, options ...jwt.ParserOption) error {
+	options = append([]jwt.ParserOption{jwt.WithValidMethods(s.GetMethods())}, options...)
+	result, err := jwt.ParseWithClaims(token, claims, s.Keyfunc, options...)
+	if err != nil {
+		return err
+	}
+	if !result.Valid {
+		return metaerror.New("invalid token")
+	}
+	return checkAccessToken(ctx, claims)
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestKeySetRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keySet := NewKeySet(NewRsaKey("rsa-1", rsaKey), NewEdDsaKey("ed-2", edKey))
+
+	oldToken, err := keySet.Sign(jwt.MapClaims{"sub": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keySet.SetSigningKey("ed-2"); err != nil {
+		t.Fatal(err)
+	}
+	newToken, err := keySet.Sign(jwt.MapClaims{"sub": "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{oldToken, newToken} {
+		claims := jwt.MapClaims{}
+		if err := keySet.Validate(token, &claims); err != nil {
+			t.Errorf("Expected token valid after rotation, err: %v", err)
+		}
+	}
+
+	keySet.RemoveKey("rsa-1")
+	if err := keySet.Validate(oldToken, &jwt.MapClaims{}); err == nil {
+		t.Errorf("Expected token signed by removed key to be rejected")
+	}
+}
+
+func TestKeySetRejectAlgorithmConfusion(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keySet := NewKeySet(NewRsaKey("rsa-1", rsaKey), NewHmacKey("hs-1", []byte("secret")))
+
+	// 使用 RSA 公钥作为 HMAC 密钥伪造 token
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "1"})
+	token.Header["kid"] = "rsa-1"
+	forged, err := token.SignedString([]byte(fmt.Sprint(rsaKey.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keySet.Validate(forged, &jwt.MapClaims{}); err == nil {
+		t.Errorf("Expected token with mismatched algorithm to be rejected")
+	}
+}
+
+func TestParseJwks(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := fmt.Sprintf(
+		`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"ed-1","use":"sig","x":"%s"}]}`,
+		base64.RawURLEncoding.EncodeToString(publicKey),
+	)
+	keys, err := ParseJwks([]byte(jwks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewKeySet(NewEdDsaKey("ed-1", privateKey))
+	token, err := signer.Sign(jwt.MapClaims{"sub": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewKeySet(keys...).Validate(token, &jwt.MapClaims{}); err != nil {
+		t.Errorf("Expected token valid with jwks key, err: %v", err)
+	}
+}
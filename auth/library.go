@@ -1,10 +1,14 @@
 package auth
 
 import (
+	"context"
 	"github.com/golang-jwt/jwt/v5"
 	metaerror "meta/meta-error"
 )
 
+// RevocationChecker 设置后，ValidateJWT 与 KeySet.Validate 会在签名校验通过后检查 token 是否已被吊销
+var RevocationChecker func(ctx context.Context, claims jwt.Claims) error
+
 func GetToken(claims jwt.Claims, secret []byte) (
 	*string,
 	error,
@@ -18,6 +22,11 @@ func GetToken(claims jwt.Claims, secret []byte) (
 }
 
 func ValidateJWT(token string, claims jwt.Claims, keyFunc jwt.Keyfunc) error {
+	return ValidateJWTContext(context.Background(), token, claims, keyFunc)
+}
+
+// ValidateJWTContext 校验 token，ctx 用于吊销检查
+func ValidateJWTContext(ctx context.Context, token string, claims jwt.Claims, keyFunc jwt.Keyfunc) error {
 	result, err := jwt.ParseWithClaims(token, claims, keyFunc)
 	if err != nil {
 		return err
@@ -25,5 +34,16 @@ func ValidateJWT(token string, claims jwt.Claims, keyFunc jwt.Keyfunc) error {
 	if !result.Valid {
 		return metaerror.New("invalid token")
 	}
-	return nil
+	return checkAccessToken(ctx, claims)
+}
+
+// checkAccessToken 拒绝刷新令牌，并在设置了 RevocationChecker 时检查吊销
+func checkAccessToken(ctx context.Context, claims jwt.Claims) error {
+	if getTokenInfo(claims).Type == tokenTypeRefresh {
+		return ErrRefreshTokenAsAccess
+	}
+	if RevocationChecker == nil {
+		return nil
+	}
+	return RevocationChecker(ctx, claims)
 }
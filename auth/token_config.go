@@ -0,0 +1,10 @@
+package auth
+
+import "time"
+
+type TokenConfig struct {
+	Issuer      string        `yaml:"issuer"`       // 签发者
+	AccessTtl   time.Duration `yaml:"access-ttl"`   // 访问令牌有效期
+	RefreshTtl  time.Duration `yaml:"refresh-ttl"`  // 刷新令牌有效期，每次刷新重新计算
+	RedisPrefix string        `yaml:"redis-prefix"` // Redis 键前缀
+}
@@ -0,0 +1,425 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	metaerror "meta/meta-error"
+	metaredis "meta/meta-redis"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	tokenTypeRefresh = "refresh"
+)
+
+var (
+	ErrTokenRevoked         = errors.New("token revoked")
+	ErrRefreshTokenReused   = errors.New("refresh token reused")
+	ErrRefreshTokenInvalid  = errors.New("refresh token invalid")
+	ErrRefreshTokenAsAccess = errors.New("refresh token can not be used as access token")
+)
+
+// AccessClaims 访问令牌的 claims
+type AccessClaims struct {
+	jwt.RegisteredClaims
+	Family     string         `json:"fam,omitempty"`
+	IssuedAtMs int64          `json:"iat_ms,omitempty"` // 毫秒级签发时间，iat 只精确到秒
+	Data       map[string]any `json:"data,omitempty"`
+}
+
+// RefreshClaims 刷新令牌的 claims，同一次登录签发的刷新令牌属于同一个 family
+type RefreshClaims struct {
+	jwt.RegisteredClaims
+	Family     string `json:"fam"`
+	Type       string `json:"typ"`
+	IssuedAtMs int64  `json:"iat_ms,omitempty"` // 毫秒级签发时间，iat 只精确到秒
+}
+
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// TokenService 签发访问令牌与刷新令牌，刷新令牌在 Redis 中按 family 保存
+// 每次刷新都会轮换刷新令牌，旧令牌再次使用视为泄露，整个 family 被吊销
+type TokenService struct {
+	config *TokenConfig
+	keySet *KeySet
+	client *redis.Client
+	now    func() time.Time
+}
+
+// revokeFamilyScript family 存在时标记为已吊销，不改变其过期时间
+var revokeFamilyScript = redis.NewScript(
+	`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'revoked', '1')
+return 1
+`,
+)
+
+// rotateScript 比较并替换 family 当前的刷新令牌，不一致时吊销 family
+// 返回 1 成功，0 令牌被重复使用，-1 family 不存在，-2 family 已吊销
+var rotateScript = redis.NewScript(
+	`
+local current = redis.call('HGET', KEYS[1], 'current')
+if not current then
+	return -1
+end
+if redis.call('HGET', KEYS[1], 'revoked') == '1' then
+	return -2
+end
+if current ~= ARGV[1] then
+	redis.call('HSET', KEYS[1], 'revoked', '1')
+	return 0
+end
+redis.call('HSET', KEYS[1], 'current', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`,
+)
+
+// NewTokenService client 为空时使用 metaredis 子系统的连接，config 不会被修改
+func NewTokenService(config *TokenConfig, keySet *KeySet, client *redis.Client) (*TokenService, error) {
+	if client == nil {
+		redisSubsystem := metaredis.GetSubsystem()
+		if redisSubsystem == nil || redisSubsystem.GetClient() == nil {
+			return nil, metaerror.New("token service redis client is nil and redis subsystem not found")
+		}
+		client = redisSubsystem.GetClient()
+	}
+	c := TokenConfig{}
+	if config != nil {
+		c = *config
+	}
+	if c.AccessTtl <= 0 {
+		c.AccessTtl = 15 * time.Minute
+	}
+	if c.RefreshTtl <= 0 {
+		c.RefreshTtl = 30 * 24 * time.Hour
+	}
+	if c.RedisPrefix == "" {
+		c.RedisPrefix = "meta:token"
+	}
+	return &TokenService{
+		config: &c,
+		keySet: keySet,
+		client: client,
+		now:    time.Now,
+	}, nil
+}
+
+// EnableRevocationCheck 将吊销检查注册到 RevocationChecker
+func (s *TokenService) EnableRevocationCheck() {
+	RevocationChecker = s.CheckRevoked
+}
+
+func (s *TokenService) getFamilyKey(family string) string {
+	return s.config.RedisPrefix + ":family:" + family
+}
+
+func (s *TokenService) getUserFamiliesKey(subject string) string {
+	return s.config.RedisPrefix + ":user:" + subject + ":families"
+}
+
+func (s *TokenService) getRevokedIdKey(id string) string {
+	return s.config.RedisPrefix + ":revoked:id:" + id
+}
+
+func (s *TokenService) getRevokedUserKey(subject string) string {
+	return s.config.RedisPrefix + ":revoked:user:" + subject
+}
+
+func (s *TokenService) getRevokedGlobalKey() string {
+	return s.config.RedisPrefix + ":revoked:global"
+}
+
+// Issue 登录时签发一对新令牌，data 会写入访问令牌并在刷新时保留
+func (s *TokenService) Issue(ctx context.Context, subject string, data map[string]any) (*TokenPair, error) {
+	family := newTokenId()
+	refreshId := newTokenId()
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "marshal token data failed")
+	}
+	_, err = s.client.TxPipelined(
+		ctx, func(pipe redis.Pipeliner) error {
+			familyKey := s.getFamilyKey(family)
+			pipe.HSet(ctx, familyKey, "subject", subject, "current", refreshId, "revoked", "0", "data", string(dataBytes))
+			pipe.PExpire(ctx, familyKey, s.config.RefreshTtl)
+			userKey := s.getUserFamiliesKey(subject)
+			pipe.SAdd(ctx, userKey, family)
+			pipe.PExpire(ctx, userKey, s.config.RefreshTtl)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "save token family failed, subject:%s", subject)
+	}
+	return s.signPair(subject, family, refreshId, data)
+}
+
+// Refresh 使用刷新令牌换取新的一对令牌，旧刷新令牌随即失效
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims := &RefreshClaims{}
+	result, err := jwt.ParseWithClaims(
+		refreshToken, claims, s.keySet.Keyfunc,
+		jwt.WithValidMethods(s.keySet.GetMethods()),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !result.Valid || claims.Type != tokenTypeRefresh || claims.Family == "" {
+		return nil, metaerror.Wrap(metaerror.Join(ErrRefreshTokenInvalid, err), "parse refresh token failed")
+	}
+	if err := s.checkSubjectRevoked(ctx, claims.Subject, getIssuedAtMs(claims)); err != nil {
+		return nil, err
+	}
+
+	familyKey := s.getFamilyKey(claims.Family)
+	newRefreshId := newTokenId()
+	code, err := rotateScript.Run(
+		ctx, s.client, []string{familyKey},
+		claims.ID, newRefreshId, s.config.RefreshTtl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return nil, metaerror.Wrap(err, "rotate refresh token failed, family:%s", claims.Family)
+	}
+	switch code {
+	case 0:
+		slog.WarnContext(
+			ctx, "refresh token reused, family revoked",
+			"subject", claims.Subject,
+			"family", claims.Family,
+		)
+		return nil, metaerror.Wrap(ErrRefreshTokenReused, "family:%s", claims.Family)
+	case -1:
+		return nil, metaerror.Wrap(ErrRefreshTokenInvalid, "family not found:%s", claims.Family)
+	case -2:
+		return nil, metaerror.Wrap(ErrTokenRevoked, "family revoked:%s", claims.Family)
+	}
+
+	var data map[string]any
+	if dataString, err := s.client.HGet(ctx, familyKey, "data").Result(); err == nil {
+		_ = json.Unmarshal([]byte(dataString), &data)
+	}
+	return s.signPair(claims.Subject, claims.Family, newRefreshId, data)
+}
+
+// RevokeToken 吊销单个令牌，刷新令牌会吊销其整个 family
+func (s *TokenService) RevokeToken(ctx context.Context, token string) error {
+	claims := &RefreshClaims{}
+	_, err := jwt.ParseWithClaims(
+		token, claims, s.keySet.Keyfunc,
+		jwt.WithValidMethods(s.keySet.GetMethods()),
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "parse token failed")
+	}
+	if claims.Type == tokenTypeRefresh {
+		return s.RevokeFamily(ctx, claims.Family)
+	}
+	if claims.ID == "" {
+		return metaerror.New("token has no id")
+	}
+	ttl := s.config.AccessTtl
+	if claims.ExpiresAt != nil {
+		ttl = claims.ExpiresAt.Sub(s.now())
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	if err := s.client.Set(ctx, s.getRevokedIdKey(claims.ID), "1", ttl).Err(); err != nil {
+		return metaerror.Wrap(err, "revoke token failed, id:%s", claims.ID)
+	}
+	return nil
+}
+
+// RevokeFamily 吊销一次登录签发的所有令牌
+func (s *TokenService) RevokeFamily(ctx context.Context, family string) error {
+	// 检查与写入放在同一个脚本中，避免 key 在两步之间过期后被重建为永不过期
+	if err := revokeFamilyScript.Run(ctx, s.client, []string{s.getFamilyKey(family)}).Err(); err != nil {
+		return metaerror.Wrap(err, "revoke family failed, family:%s", family)
+	}
+	return nil
+}
+
+// RevokeUser 吊销用户在此刻之前签发的所有令牌，精确到毫秒
+func (s *TokenService) RevokeUser(ctx context.Context, subject string) error {
+	now := strconv.FormatInt(s.now().UnixMilli(), 10)
+	if err := s.client.Set(ctx, s.getRevokedUserKey(subject), now, s.config.RefreshTtl).Err(); err != nil {
+		return metaerror.Wrap(err, "revoke user failed, subject:%s", subject)
+	}
+	families, err := s.client.SMembers(ctx, s.getUserFamiliesKey(subject)).Result()
+	if err != nil {
+		return metaerror.Wrap(err, "get user families failed, subject:%s", subject)
+	}
+	var finalErr error
+	for _, family := range families {
+		finalErr = metaerror.Join(finalErr, s.RevokeFamily(ctx, family))
+	}
+	return finalErr
+}
+
+// RevokeAll 吊销此刻之前签发的所有令牌，精确到毫秒
+func (s *TokenService) RevokeAll(ctx context.Context) error {
+	now := strconv.FormatInt(s.now().UnixMilli(), 10)
+	if err := s.client.Set(ctx, s.getRevokedGlobalKey(), now, s.config.RefreshTtl).Err(); err != nil {
+		return metaerror.Wrap(err, "revoke all failed")
+	}
+	return nil
+}
+
+// CheckRevoked 检查访问令牌是否已被吊销，可作为 RevocationChecker 使用
+func (s *TokenService) CheckRevoked(ctx context.Context, claims jwt.Claims) error {
+	info := getTokenInfo(claims)
+	if info.Type == tokenTypeRefresh {
+		return ErrRefreshTokenAsAccess
+	}
+	subject, _ := claims.GetSubject()
+	if err := s.checkSubjectRevoked(ctx, subject, getIssuedAtMs(claims)); err != nil {
+		return err
+	}
+	if info.ID != "" {
+		exists, err := s.client.Exists(ctx, s.getRevokedIdKey(info.ID)).Result()
+		if err != nil {
+			return metaerror.Wrap(err, "check revoked token failed")
+		}
+		if exists > 0 {
+			return ErrTokenRevoked
+		}
+	}
+	if info.Family != "" {
+		revoked, err := s.client.HGet(ctx, s.getFamilyKey(info.Family), "revoked").Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return metaerror.Wrap(err, "check revoked family failed")
+		}
+		if revoked == "1" {
+			return ErrTokenRevoked
+		}
+	}
+	return nil
+}
+
+// checkSubjectRevoked 检查全局与用户级别的吊销时间（毫秒），签发时间不晚于吊销时间的令牌无效
+func (s *TokenService) checkSubjectRevoked(ctx context.Context, subject string, issuedAtMs int64) error {
+	keys := []string{s.getRevokedGlobalKey()}
+	if subject != "" {
+		keys = append(keys, s.getRevokedUserKey(subject))
+	}
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return metaerror.Wrap(err, "check revoked subject failed")
+	}
+	for _, value := range values {
+		revokedAtString, ok := value.(string)
+		if !ok {
+			continue
+		}
+		revokedAt, err := strconv.ParseInt(revokedAtString, 10, 64)
+		if err != nil {
+			continue
+		}
+		if issuedAtMs <= revokedAt {
+			return ErrTokenRevoked
+		}
+	}
+	return nil
+}
+
+func (s *TokenService) signPair(subject string, family string, refreshId string, data map[string]any) (*TokenPair, error) {
+	now := s.now()
+	accessExpiresAt := now.Add(s.config.AccessTtl)
+	accessToken, err := s.keySet.Sign(
+		&AccessClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    s.config.Issuer,
+				Subject:   subject,
+				ID:        newTokenId(),
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(accessExpiresAt),
+			},
+			Family:     family,
+			IssuedAtMs: now.UnixMilli(),
+			Data:       data,
+		},
+	)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "sign access token failed")
+	}
+	refreshExpiresAt := now.Add(s.config.RefreshTtl)
+	refreshToken, err := s.keySet.Sign(
+		&RefreshClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    s.config.Issuer,
+				Subject:   subject,
+				ID:        refreshId,
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(refreshExpiresAt),
+			},
+			Family:     family,
+			Type:       tokenTypeRefresh,
+			IssuedAtMs: now.UnixMilli(),
+		},
+	)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "sign refresh token failed")
+	}
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+type tokenInfo struct {
+	ID         string `json:"jti"`
+	Family     string `json:"fam"`
+	Type       string `json:"typ"`
+	IssuedAtMs int64  `json:"iat_ms"`
+}
+
+// getTokenInfo 读取任意 claims 中的 jti、fam、typ 与 iat_ms
+func getTokenInfo(claims jwt.Claims) tokenInfo {
+	var info tokenInfo
+	switch v := claims.(type) {
+	case *AccessClaims:
+		info.ID, info.Family, info.IssuedAtMs = v.ID, v.Family, v.IssuedAtMs
+	case *RefreshClaims:
+		info.ID, info.Family, info.Type, info.IssuedAtMs = v.ID, v.Family, v.Type, v.IssuedAtMs
+	default:
+		data, err := json.Marshal(claims)
+		if err == nil {
+			_ = json.Unmarshal(data, &info)
+		}
+	}
+	return info
+}
+
+// getIssuedAtMs 返回毫秒级签发时间，缺少 iat_ms 时退化为 iat，都缺少时返回 0
+func getIssuedAtMs(claims jwt.Claims) int64 {
+	if issuedAtMs := getTokenInfo(claims).IssuedAtMs; issuedAtMs > 0 {
+		return issuedAtMs
+	}
+	if issuedAt, err := claims.GetIssuedAt(); err == nil && issuedAt != nil {
+		return issuedAt.UnixMilli()
+	}
+	return 0
+}
+
+func newTokenId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestTokenService(t *testing.T) (*TokenService, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(
+		func() {
+			_ = client.Close()
+		},
+	)
+	keySet := NewKeySet(NewHmacKey("k1", []byte("secret")))
+	service, err := NewTokenService(&TokenConfig{}, keySet, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, server
+}
+
+func checkAccess(s *TokenService, token string) error {
+	claims := &AccessClaims{}
+	if err := s.keySet.Validate(token, claims); err != nil {
+		return err
+	}
+	return s.CheckRevoked(context.Background(), claims)
+}
+
+func TestTokenServiceRotation(t *testing.T) {
+	s, server := newTestTokenService(t)
+	ctx := context.Background()
+
+	pair, err := s.Issue(ctx, "u1", map[string]any{"role": "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPair, err := s.Refresh(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := &AccessClaims{}
+	if err := s.keySet.Validate(newPair.AccessToken, claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Data["role"] != "admin" {
+		t.Errorf("data not kept after refresh: %v", claims.Data)
+	}
+
+	// 旧刷新令牌再次使用，整个 family 被吊销
+	if _, err := s.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reuse err = %v", err)
+	}
+	if _, err := s.Refresh(ctx, newPair.RefreshToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("refresh after reuse err = %v", err)
+	}
+	if err := checkAccess(s, newPair.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("access after reuse err = %v", err)
+	}
+
+	familyKey := s.getFamilyKey(claims.Family)
+	if server.TTL(familyKey) <= 0 {
+		t.Error("revoked family should keep its ttl")
+	}
+	if err := s.RevokeFamily(ctx, "missing"); err != nil {
+		t.Fatal(err)
+	}
+	if server.Exists(s.getFamilyKey("missing")) {
+		t.Error("revoking missing family should not create it")
+	}
+}
+
+func TestTokenServiceRevokeUserSameSecond(t *testing.T) {
+	s, _ := newTestTokenService(t)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second).Add(100 * time.Millisecond)
+	s.now = func() time.Time {
+		return now
+	}
+
+	oldPair, err := s.Issue(ctx, "u1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(200 * time.Millisecond)
+	if err := s.RevokeUser(ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	// 同一秒内重新登录
+	now = now.Add(200 * time.Millisecond)
+	newPair, err := s.Issue(ctx, "u1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkAccess(s, oldPair.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("old access err = %v", err)
+	}
+	if _, err := s.Refresh(ctx, oldPair.RefreshToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("old refresh err = %v", err)
+	}
+	if err := checkAccess(s, newPair.AccessToken); err != nil {
+		t.Errorf("new access err = %v", err)
+	}
+	if _, err := s.Refresh(ctx, newPair.RefreshToken); err != nil {
+		t.Errorf("new refresh err = %v", err)
+	}
+}
+
+func TestRefreshTokenAsAccess(t *testing.T) {
+	s, _ := newTestTokenService(t)
+	pair, err := s.Issue(context.Background(), "u1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 未设置 RevocationChecker 时也要拒绝
+	if err := s.keySet.Validate(pair.RefreshToken, &jwt.MapClaims{}); !errors.Is(err, ErrRefreshTokenAsAccess) {
+		t.Errorf("KeySet.Validate err = %v", err)
+	}
+	if err := ValidateJWT(pair.RefreshToken, &RefreshClaims{}, s.keySet.Keyfunc); !errors.Is(err, ErrRefreshTokenAsAccess) {
+		t.Errorf("ValidateJWT err = %v", err)
+	}
+	if err := s.keySet.Validate(pair.AccessToken, &jwt.MapClaims{}); err != nil {
+		t.Errorf("access token err = %v", err)
+	}
+}
+
+func TestNewTokenServiceWithoutRedis(t *testing.T) {
+	if _, err := NewTokenService(&TokenConfig{}, nil, nil); err == nil {
+		t.Fatal("missing redis should return error")
+	}
+	config := &TokenConfig{}
+	if _, err := NewTokenService(config, nil, redis.NewClient(&redis.Options{})); err != nil || config.AccessTtl != 0 {
+		t.Fatalf("config should not be modified: %v %+v", err, config)
+	}
+}
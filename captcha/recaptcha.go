@@ -0,0 +1,11 @@
+package captcha
+
+const RecaptchaEndpoint = "https://recaptcha.net/recaptcha/api/siteverify"
+
+type RecaptchaVerifier struct {
+	verifier
+}
+
+func NewRecaptchaVerifier(config *Config) *RecaptchaVerifier {
+	return &RecaptchaVerifier{verifier: newVerifier(config, RecaptchaEndpoint)}
+}
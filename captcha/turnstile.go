@@ -0,0 +1,11 @@
+package captcha
+
+const TurnstileEndpoint = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+
+type TurnstileVerifier struct {
+	verifier
+}
+
+func NewTurnstileVerifier(config *Config) *TurnstileVerifier {
+	return &TurnstileVerifier{verifier: newVerifier(config, TurnstileEndpoint)}
+}
@@ -0,0 +1,135 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	metaerror "meta/meta-error"
+	metapanic "meta/meta-panic"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrVerifyFailed     = errors.New("captcha verify failed")
+	ErrScoreTooLow      = errors.New("captcha score too low")
+	ErrActionInvalid    = errors.New("captcha action mismatch")
+	ErrHostnameInvalid  = errors.New("captcha hostname mismatch")
+	ErrUnexpectedStatus = errors.New("captcha verify unexpected status code")
+)
+
+type Result struct {
+	Success     bool     `json:"success"`
+	Score       float64  `json:"score"` // 仅 reCAPTCHA v3 返回
+	Action      string   `json:"action"`
+	Hostname    string   `json:"hostname"`
+	ChallengeTs string   `json:"challenge_ts"`
+	ErrorCodes  []string `json:"error-codes"`
+}
+
+// Verifier 校验客户端提交的验证码 token
+type Verifier interface {
+	// Verify 请求服务端校验，仅在请求失败时返回错误
+	Verify(ctx context.Context, token string, remoteIp string) (*Result, error)
+	// Check 根据配置的阈值判断校验结果是否通过
+	Check(result *Result) error
+}
+
+type Config struct {
+	Secret    string        `yaml:"secret"`    // 服务端密钥
+	Endpoint  string        `yaml:"endpoint"`  // 校验地址，为空使用官方地址
+	Timeout   time.Duration `yaml:"timeout"`   // 校验请求超时，默认 5 秒
+	MinScore  float64       `yaml:"min-score"` // 最低分数，仅对返回分数的 reCAPTCHA v3 生效
+	Action    string        `yaml:"action"`    // 要求的 action，为空不校验
+	Hostnames []string      `yaml:"hostnames"` // 允许的 hostname，为空不校验
+
+	HttpClient *http.Client `yaml:"-"` // 默认为 http.DefaultClient
+}
+
+type verifier struct {
+	config *Config
+}
+
+func newVerifier(config *Config, defaultEndpoint string) verifier {
+	if config.Endpoint == "" {
+		config.Endpoint = defaultEndpoint
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.HttpClient == nil {
+		config.HttpClient = http.DefaultClient
+	}
+	return verifier{config: config}
+}
+
+// Verify Turnstile 与 reCAPTCHA 使用相同的表单参数
+func (v verifier) Verify(ctx context.Context, token string, remoteIp string) (*Result, error) {
+	form := url.Values{}
+	form.Set("secret", v.config.Secret)
+	form.Set("response", token)
+	if remoteIp != "" {
+		form.Set("remoteip", remoteIp)
+	}
+	return v.post(ctx, form)
+}
+
+func (v verifier) post(ctx context.Context, form url.Values) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, v.config.Timeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, v.config.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, metaerror.Wrap(err, "create captcha verify request failed")
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := v.config.HttpClient.Do(request)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "captcha verify request failed")
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			metapanic.ProcessError(err)
+		}
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, metaerror.Wrap(ErrUnexpectedStatus, "status code:%d", resp.StatusCode)
+	}
+	result := &Result{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, metaerror.Wrap(err, "decode captcha verify response failed")
+	}
+	return result, nil
+}
+
+func (v verifier) Check(result *Result) error {
+	if !result.Success {
+		return metaerror.Wrap(ErrVerifyFailed, "error codes:%v", result.ErrorCodes)
+	}
+	if v.config.MinScore > 0 && result.Score < v.config.MinScore {
+		return metaerror.Wrap(ErrScoreTooLow, "score:%v, min:%v", result.Score, v.config.MinScore)
+	}
+	if v.config.Action != "" && result.Action != v.config.Action {
+		return metaerror.Wrap(ErrActionInvalid, "action:%s, want:%s", result.Action, v.config.Action)
+	}
+	if len(v.config.Hostnames) > 0 && !slices.Contains(v.config.Hostnames, result.Hostname) {
+		return metaerror.Wrap(ErrHostnameInvalid, "hostname:%s", result.Hostname)
+	}
+	return nil
+}
+
+// VerifyToken 校验 token 并检查结果
+func VerifyToken(ctx context.Context, verifier Verifier, token string, remoteIp string) error {
+	if token == "" {
+		return metaerror.Wrap(ErrVerifyFailed, "token is empty")
+	}
+	result, err := verifier.Verify(ctx, token, remoteIp)
+	if err != nil {
+		return err
+	}
+	return verifier.Check(result)
+}
@@ -0,0 +1,78 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newStubServer(t *testing.T, body string, delay time.Duration) *httptest.Server {
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil || r.PostForm.Get("secret") != "secret" {
+					t.Errorf("unexpected verify request: %v", r.PostForm)
+				}
+				time.Sleep(delay)
+				_, _ = w.Write([]byte(body))
+			},
+		),
+	)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecaptchaVerifier(t *testing.T) {
+	server := newStubServer(t, `{"success":true,"score":0.3,"action":"login","hostname":"example.com"}`, 0)
+	tests := []struct {
+		config *Config
+		err    error
+	}{
+		{&Config{}, nil},
+		{&Config{MinScore: 0.5}, ErrScoreTooLow},
+		{&Config{Action: "register"}, ErrActionInvalid},
+		{&Config{Hostnames: []string{"other.com"}}, ErrHostnameInvalid},
+		{&Config{MinScore: 0.3, Action: "login", Hostnames: []string{"example.com"}}, nil},
+	}
+	for i, test := range tests {
+		test.config.Secret = "secret"
+		test.config.Endpoint = server.URL
+		err := VerifyToken(context.Background(), NewRecaptchaVerifier(test.config), "token", "127.0.0.1")
+		if !errors.Is(err, test.err) {
+			t.Errorf("case %d: got %v, want %v", i, err, test.err)
+		}
+	}
+}
+
+func TestTurnstileVerifier(t *testing.T) {
+	server := newStubServer(t, `{"success":false,"error-codes":["invalid-input-response"]}`, 0)
+	verifier := NewTurnstileVerifier(&Config{Secret: "secret", Endpoint: server.URL})
+	if err := VerifyToken(context.Background(), verifier, "token", ""); !errors.Is(err, ErrVerifyFailed) {
+		t.Errorf("got %v, want ErrVerifyFailed", err)
+	}
+	if err := VerifyToken(context.Background(), verifier, "", ""); !errors.Is(err, ErrVerifyFailed) {
+		t.Errorf("empty token got %v, want ErrVerifyFailed", err)
+	}
+
+	slowServer := newStubServer(t, `{"success":true}`, 200*time.Millisecond)
+	verifier = NewTurnstileVerifier(&Config{Secret: "secret", Endpoint: slowServer.URL, Timeout: 50 * time.Millisecond})
+	if _, err := verifier.Verify(context.Background(), "token", ""); err == nil {
+		t.Error("verify should time out")
+	}
+
+	errorServer := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+		),
+	)
+	t.Cleanup(errorServer.Close)
+	verifier = NewTurnstileVerifier(&Config{Secret: "secret", Endpoint: errorServer.URL})
+	if _, err := verifier.Verify(context.Background(), "token", ""); !errors.Is(err, ErrUnexpectedStatus) {
+		t.Errorf("got %v, want ErrUnexpectedStatus", err)
+	}
+}
@@ -111,7 +111,9 @@ func Start(startFuncPre func() error, startFuncEnd func() error, stopImmediately
 		pendingStop()
 	}
 
-	for _, s := range subsystems {
+	// 按注册的逆序停止，依赖其他子系统的子系统先停止
+	for i := len(subsystems) - 1; i >= 0; i-- {
+		s := subsystems[i]
 		err := s.Stop()
 		if err != nil {
 			slog.Error("engine stop error", "subsystem", s.GetName(), "err", err)
@@ -120,6 +122,10 @@ func Start(startFuncPre func() error, startFuncEnd func() error, stopImmediately
 
 	slog.Info("engine stop")
 
+	if err := metalog.Close(); err != nil {
+		slog.Error("engine close log error", "err", err)
+	}
+
 	return nil
 }
 
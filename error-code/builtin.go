@@ -0,0 +1,68 @@
+package metaerrorcode
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// 框架保留 0 到 1999，其中 100 到 599 对应同名的 HTTP 状态码
+var builtinRange = MustNewRange("meta", 0, 1999)
+
+func init() {
+	builtinRange.MustRegister(
+		&Definition{
+			Code: Success, Name: "Success", Level: slog.LevelDebug,
+			Message: "success", Messages: map[string]string{"zh": "成功"},
+		},
+		&Definition{
+			Code: CommonError, Name: "CommonError", HttpStatus: http.StatusInternalServerError, Level: slog.LevelError,
+			Message: "request failed", Messages: map[string]string{"zh": "请求失败"},
+		},
+		&Definition{
+			Code: PanicError, Name: "PanicError", HttpStatus: http.StatusInternalServerError, Level: slog.LevelError,
+			Message: "internal server error", Messages: map[string]string{"zh": "服务器内部错误"},
+		},
+		&Definition{
+			Code: UnknownError, Name: "UnknownError", HttpStatus: http.StatusInternalServerError, Level: slog.LevelError,
+			Message: "unknown error", Messages: map[string]string{"zh": "未知错误"},
+		},
+		&Definition{
+			Code: TooManyRequests, Name: "TooManyRequests", HttpStatus: http.StatusTooManyRequests, Level: slog.LevelInfo,
+			Message: "too many requests", Messages: map[string]string{"zh": "请求过于频繁"},
+		},
+		&Definition{
+			Code: ParamError, Name: "ParamError", HttpStatus: http.StatusBadRequest, Level: slog.LevelInfo,
+			Message: "invalid parameter", Messages: map[string]string{"zh": "参数错误"},
+		},
+		&Definition{
+			Code: CaptchaError, Name: "CaptchaError", HttpStatus: http.StatusBadRequest, Level: slog.LevelInfo,
+			Message: "captcha verification failed", Messages: map[string]string{"zh": "人机验证失败"},
+		},
+	)
+	for _, item := range []struct {
+		status  int
+		level   slog.Level
+		message string
+	}{
+		{http.StatusBadRequest, slog.LevelInfo, "请求错误"},
+		{http.StatusUnauthorized, slog.LevelInfo, "未登录或登录已过期"},
+		{http.StatusForbidden, slog.LevelInfo, "没有权限"},
+		{http.StatusNotFound, slog.LevelInfo, "资源不存在"},
+		{http.StatusMethodNotAllowed, slog.LevelInfo, "不支持的请求方法"},
+		{http.StatusConflict, slog.LevelInfo, "资源冲突"},
+		{http.StatusTooManyRequests, slog.LevelInfo, "请求过于频繁"},
+		{http.StatusInternalServerError, slog.LevelError, "服务器内部错误"},
+		{http.StatusServiceUnavailable, slog.LevelWarn, "服务暂不可用"},
+	} {
+		builtinRange.MustRegister(
+			&Definition{
+				Code:       ErrorCode(item.status),
+				Name:       http.StatusText(item.status),
+				HttpStatus: item.status,
+				Level:      item.level,
+				Message:    http.StatusText(item.status),
+				Messages:   map[string]string{"zh": item.message},
+			},
+		)
+	}
+}
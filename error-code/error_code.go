@@ -12,4 +12,6 @@ const (
 	PanicError      ErrorCode = 1001
 	UnknownError    ErrorCode = 1002
 	TooManyRequests ErrorCode = 1003 // 太过频繁
+	ParamError      ErrorCode = 1004 // 参数错误
+	CaptchaError    ErrorCode = 1005 // 人机验证失败
 )
@@ -0,0 +1,160 @@
+package metaerrorcode
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Definition 错误码的定义
+type Definition struct {
+	Code       ErrorCode
+	Name       string
+	HttpStatus int               // 响应的 HTTP 状态码，默认为 200
+	Message    string            // 默认提示文本
+	Level      slog.Level        // 出错时的日志级别，Error 及以上会触发告警
+	Messages   map[string]string // 按语言区域的提示文本，如 zh、zh-TW、en
+}
+
+// Range 某个服务或模块独占的错误码区间，左闭右闭
+type Range struct {
+	Owner string
+	Start ErrorCode
+	End   ErrorCode
+}
+
+var (
+	registryMutex sync.RWMutex
+	ranges        []*Range
+	definitions   = make(map[ErrorCode]*Definition)
+)
+
+// NewRange 申请错误码区间，与已有区间重叠时返回错误
+func NewRange(owner string, start ErrorCode, end ErrorCode) (*Range, error) {
+	if start > end {
+		return nil, fmt.Errorf("error code range invalid, owner:%s, start:%d, end:%d", owner, start, end)
+	}
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	for _, r := range ranges {
+		if start <= r.End && r.Start <= end {
+			return nil, fmt.Errorf(
+				"error code range [%d, %d] of %s overlaps [%d, %d] of %s",
+				start, end, owner, r.Start, r.End, r.Owner,
+			)
+		}
+	}
+	r := &Range{Owner: owner, Start: start, End: end}
+	ranges = append(ranges, r)
+	return r, nil
+}
+
+// MustNewRange 用于包初始化，失败时 panic
+func MustNewRange(owner string, start ErrorCode, end ErrorCode) *Range {
+	r, err := NewRange(owner, start, end)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Register 注册区间内的错误码，超出区间或重复注册时返回错误
+func (r *Range) Register(definitions ...*Definition) error {
+	for _, definition := range definitions {
+		if definition.Code < r.Start || definition.Code > r.End {
+			return fmt.Errorf(
+				"error code %d out of range [%d, %d] of %s",
+				definition.Code, r.Start, r.End, r.Owner,
+			)
+		}
+	}
+	return register(definitions...)
+}
+
+// MustRegister 用于包初始化，失败时 panic
+func (r *Range) MustRegister(definitions ...*Definition) {
+	if err := r.Register(definitions...); err != nil {
+		panic(err)
+	}
+}
+
+func register(newDefinitions ...*Definition) error {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	for _, definition := range newDefinitions {
+		if _, ok := definitions[definition.Code]; ok {
+			return fmt.Errorf("error code %d already registered", definition.Code)
+		}
+	}
+	for _, definition := range newDefinitions {
+		if definition.HttpStatus == 0 {
+			definition.HttpStatus = http.StatusOK
+		}
+		definitions[definition.Code] = definition
+	}
+	return nil
+}
+
+func GetDefinition[T Numeric](code T) (*Definition, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	definition, ok := definitions[ErrorCode(code)]
+	return definition, ok
+}
+
+// GetDefinitions 返回所有已注册的错误码，按错误码排序
+func GetDefinitions() []*Definition {
+	registryMutex.RLock()
+	result := make([]*Definition, 0, len(definitions))
+	for _, definition := range definitions {
+		result = append(result, definition)
+	}
+	registryMutex.RUnlock()
+	sort.Slice(
+		result, func(i, j int) bool {
+			return result[i].Code < result[j].Code
+		},
+	)
+	return result
+}
+
+// GetHttpStatus 未注册的错误码返回 200
+func GetHttpStatus[T Numeric](code T) int {
+	if definition, ok := GetDefinition(code); ok {
+		return definition.HttpStatus
+	}
+	return http.StatusOK
+}
+
+// GetLevel 未注册的错误码视为 Error 级别
+func GetLevel[T Numeric](code T) slog.Level {
+	if definition, ok := GetDefinition(code); ok {
+		return definition.Level
+	}
+	return slog.LevelError
+}
+
+// GetMessage 按 locales 的顺序查找提示文本，依次尝试完整区域与语言部分，都没有时返回默认文本
+func GetMessage[T Numeric](code T, locales ...string) string {
+	definition, ok := GetDefinition(code)
+	if !ok {
+		return ""
+	}
+	for _, locale := range locales {
+		locale = strings.ToLower(locale)
+		for key, message := range definition.Messages {
+			if strings.ToLower(key) == locale {
+				return message
+			}
+		}
+		if i := strings.IndexAny(locale, "-_"); i > 0 {
+			if message, ok := definition.Messages[locale[:i]]; ok {
+				return message
+			}
+		}
+	}
+	return definition.Message
+}
@@ -0,0 +1,44 @@
+package metaerrorcode
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	if _, err := NewRange("overlap", 1500, 2500); err == nil {
+		t.Error("range overlapping builtin range should fail")
+	}
+	r, err := NewRange("test", 900000, 900099)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(&Definition{Code: 900100}); err == nil {
+		t.Error("code out of range should fail")
+	}
+	if err := r.Register(&Definition{Code: 900001, Message: "not found", Messages: map[string]string{"zh": "不存在", "zh-TW": "不存在的"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(&Definition{Code: 900001}); err == nil {
+		t.Error("duplicate code should fail")
+	}
+	if GetHttpStatus(900001) != http.StatusOK {
+		t.Error("default http status should be 200")
+	}
+
+	tests := []struct {
+		locales []string
+		want    string
+	}{
+		{nil, "not found"},
+		{[]string{"zh-CN"}, "不存在"},
+		{[]string{"zh-tw"}, "不存在的"},
+		{[]string{"fr", "zh"}, "不存在"},
+		{[]string{"fr"}, "not found"},
+	}
+	for _, test := range tests {
+		if got := GetMessage(900001, test.locales...); got != test.want {
+			t.Errorf("GetMessage(%v) = %s, want %s", test.locales, got, test.want)
+		}
+	}
+}
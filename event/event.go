@@ -1,8 +1,6 @@
 package event
 
 import (
-	"log/slog"
-	metaflag "meta/meta-flag"
 	metaformat "meta/meta-format"
 	set "meta/meta-set"
 	metastring "meta/meta-string"
@@ -56,8 +54,8 @@ func (e *Event) InvokeChannel(eventType reflect.Type, channels *[]string, p ...P
 		},
 	)
 
-	if metaflag.IsDebugEvent() {
-		slog.Info(
+	if isLogDebug() {
+		logDebug(
 			"[Event] Invoke",
 			"event", eventType.String(),
 			"channels", channels,
@@ -66,11 +64,19 @@ func (e *Event) InvokeChannel(eventType reflect.Type, channels *[]string, p ...P
 		)
 	}
 
+	eventInvocations.WithLabelValues(eventType.String()).Inc()
 	for _, l := range finalListeners {
 		l.OnEventInvoked(eventType, p...)
 	}
 }
 
+// HasChannelListener 返回指定频道是否注册了监听者
+func (e *Event) HasChannelListener(channel string) bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return len(e.channelListeners[channel]) > 0
+}
+
 func (e *Event) Register(eventType reflect.Type, l ListenerInterface, channel ...string) {
 	e.mutex.Lock()
 	if len(channel) == 0 {
@@ -88,8 +94,8 @@ func (e *Event) Register(eventType reflect.Type, l ListenerInterface, channel ..
 	}
 	e.mutex.Unlock()
 
-	if metaflag.IsDebugEvent() {
-		slog.Info(
+	if isLogDebug() {
+		logDebug(
 			"[Event] Register listener",
 			"event", eventType.String(),
 			"listener", l.GetName(),
@@ -111,8 +117,8 @@ func (e *Event) Unregister(eventType reflect.Type, l ListenerInterface, channel
 	}
 	e.mutex.Unlock()
 
-	if metaflag.IsDebugEvent() {
-		slog.Info(
+	if isLogDebug() {
+		logDebug(
 			"[Event] Unregister listener",
 			"event", eventType.String(),
 			"listener", l.GetName(),
@@ -21,3 +21,21 @@ func TestBase(t *testing.T) {
 	UnregisterListener[TestEvent](l)
 	Invoke[TestEvent]()
 }
+
+type TestChannelEvent struct {
+	Event
+}
+
+func TestHasChannelListener(t *testing.T) {
+	if HasChannelListener[TestChannelEvent]("id-1") {
+		t.Error("event without listener should report false")
+	}
+	l := NewListenerDefault(
+		func(event reflect.Type, p ...Payload) {
+		},
+	)
+	RegisterListener[TestChannelEvent](l, "id-1")
+	if !HasChannelListener[TestChannelEvent]("id-1") || HasChannelListener[TestChannelEvent]("id-2") {
+		t.Error("only id-1 should have listener")
+	}
+}
@@ -1,9 +1,7 @@
 package event
 
 import (
-	"log/slog"
 	metaerror "meta/meta-error"
-	metaflag "meta/meta-flag"
 	metaformat "meta/meta-format"
 	"reflect"
 	"sync"
@@ -72,8 +70,8 @@ func Invoke[T any, _ interface {
 	eventType := reflect.TypeFor[T]()
 	event := GetEvent(eventType)
 	if event == nil {
-		if metaflag.IsDebugEvent() {
-			slog.Info(
+		if isLogDebug() {
+			logDebug(
 				"[Event] Invoke event without listener",
 				"event", eventType.String(),
 				"payload", metaformat.FormatByJson(p),
@@ -91,8 +89,8 @@ func InvokeChannel[T any, _ interface {
 	eventType := reflect.TypeFor[T]()
 	event := GetEvent(eventType)
 	if event == nil {
-		if metaflag.IsDebugEvent() {
-			slog.Info(
+		if isLogDebug() {
+			logDebug(
 				"[Event] Invoke event with channel without listener",
 				"event", eventType.String(),
 				"channels", channels,
@@ -104,6 +102,15 @@ func InvokeChannel[T any, _ interface {
 	event.(Interface).InvokeChannel(eventType, channels, p...)
 }
 
+// HasChannelListener 返回事件在指定频道上是否注册了监听者
+func HasChannelListener[T any, _ interface {
+	*T
+	Interface
+}](channel string) bool {
+	event, ok := GetEvent(reflect.TypeFor[T]()).(interface{ HasChannelListener(channel string) bool })
+	return ok && event.HasChannelListener(channel)
+}
+
 func ParsePayloadIndex[T any, _ interface {
 	*T
 }](payload []Payload, index int) (*T, error) {
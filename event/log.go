@@ -0,0 +1,24 @@
+package event
+
+import (
+	"context"
+	"log/slog"
+	metaflag "meta/meta-flag"
+	metalog "meta/meta-log"
+)
+
+var logger = metalog.GetModuleLogger(metalog.LoggerEvent)
+
+// isLogDebug 开启 --debug-event 或 event 组件等级为 debug 时输出事件日志
+func isLogDebug() bool {
+	return metaflag.IsDebugEvent() || logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// logDebug 开启 --debug-event 时以 Info 输出，保持原有行为
+func logDebug(msg string, args ...any) {
+	if metaflag.IsDebugEvent() {
+		logger.Info(msg, args...)
+	} else {
+		logger.Debug(msg, args...)
+	}
+}
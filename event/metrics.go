@@ -0,0 +1,8 @@
+package event
+
+import metametrics "meta/meta-metrics"
+
+var eventInvocations = metametrics.NewCounterVec(
+	"event_invocations_total", "Total number of event invocations.",
+	"type",
+)
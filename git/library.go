@@ -3,13 +3,14 @@ package git
 import (
 	"log/slog"
 	"meta/meta-cmd"
+	metaredact "meta/meta-redact"
 	"os"
 	"os/exec"
 	"strings"
 )
 
 func Command(name string, arg ...string) *Cmd {
-	slog.Info("git command", "cmd", name+" "+strings.Join(arg, " "))
+	slog.Info("git command", "cmd", name+" "+strings.Join(metaredact.Args(arg), " "))
 	appendArg := []string{}
 	svnCmd := &Cmd{*exec.Command(name, append(appendArg, arg...)...)}
 	logReader := &metacmd.LogReader{
@@ -0,0 +1,21 @@
+package kafka
+
+import metametrics "meta/meta-metrics"
+
+var (
+	kafkaProducedMessages = metametrics.NewCounterVec(
+		"kafka_produced_messages_total", "Total number of produced Kafka messages.",
+		"topic", "status",
+	)
+	kafkaConsumedMessages = metametrics.NewCounterVec(
+		"kafka_consumed_messages_total", "Total number of consumed Kafka messages.",
+		"topic", "status",
+	)
+)
+
+func getMetricStatus(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
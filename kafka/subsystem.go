@@ -8,10 +8,17 @@ import (
 	metaerror "meta/meta-error"
 	metapanic "meta/meta-panic"
 	metastring "meta/meta-string"
+	metatrace "meta/meta-trace"
+	metatracing "meta/meta-tracing"
+	"meta/retry"
 	"meta/subsystem"
+	"strings"
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Subsystem struct {
@@ -73,9 +80,28 @@ func (s *Subsystem) ProduceMessage(ctx context.Context, topic string, key string
 		Key:   []byte(key),
 		Value: valueJSON,
 	}
-
-	// 推送消息
-	if err := writer.WriteMessages(ctx, message); err != nil {
+	ctx, span := metatracing.StartSpan(
+		ctx, topic+" publish", trace.SpanKindProducer,
+		attribute.String("messaging.system", "kafka"),
+		attribute.String("messaging.destination.name", topic),
+	)
+	defer span.End()
+	metatrace.Inject(
+		ctx, func(key string, value string) {
+			message.Headers = append(message.Headers, kafka.Header{Key: key, Value: []byte(value)})
+		},
+	)
+
+	// 推送消息，Kafka 不可用时由熔断器直接拒绝，避免每次都等待超时
+	err = retry.Protect(
+		ctx, "kafka:"+s.Addr, func() error {
+			return writer.WriteMessages(ctx, message)
+		},
+	)
+	kafkaProducedMessages.WithLabelValues(topic, getMetricStatus(err)).Inc()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		log.Printf("Failed to produce message: %v", err)
 		return err
 	}
@@ -90,6 +116,19 @@ func (s *Subsystem) ProduceMessageSimple(ctx context.Context, topic string, valu
 
 // Subscribe 订阅消息
 func (s *Subsystem) Subscribe(groupId string, topic string, callback func(key, value string) error) error {
+	return s.SubscribeContext(
+		groupId, topic, func(ctx context.Context, key, value string) error {
+			return callback(key, value)
+		},
+	)
+}
+
+// SubscribeContext 订阅消息，回调的 ctx 中带有生产者传递的请求ID与 traceparent
+func (s *Subsystem) SubscribeContext(
+	groupId string,
+	topic string,
+	callback func(ctx context.Context, key, value string) error,
+) error {
 	for {
 		reader := kafka.NewReader(kafka.ReaderConfig{
 			Brokers: []string{s.Addr},
@@ -103,7 +142,18 @@ func (s *Subsystem) Subscribe(groupId string, topic string, callback func(key, v
 				metapanic.ProcessError(metaerror.Wrap(err))
 				break
 			}
-			if err := callback(string(message.Key), string(message.Value)); err != nil {
+			ctx := metatrace.Extract(context.Background(), getMessageHeader(message))
+			ctx, span := metatracing.StartSpan(
+				ctx, topic+" process", trace.SpanKindConsumer,
+				attribute.String("messaging.system", "kafka"),
+				attribute.String("messaging.destination.name", topic),
+				attribute.String("messaging.consumer.group.name", groupId),
+				attribute.Int64("messaging.kafka.offset", message.Offset),
+			)
+			err = callback(ctx, string(message.Key), string(message.Value))
+			metatracing.EndSpan(span, err)
+			kafkaConsumedMessages.WithLabelValues(topic, getMetricStatus(err)).Inc()
+			if err != nil {
 				metapanic.ProcessError(metaerror.Wrap(err, "error handling message, topic: %s", topic))
 			}
 		}
@@ -116,3 +166,14 @@ func (s *Subsystem) Subscribe(groupId string, topic string, callback func(key, v
 		time.Sleep(10 * time.Second)
 	}
 }
+
+func getMessageHeader(message kafka.Message) func(key string) string {
+	return func(key string) string {
+		for _, header := range message.Headers {
+			if strings.EqualFold(header.Key, key) {
+				return string(header.Value)
+			}
+		}
+		return ""
+	}
+}
@@ -0,0 +1,88 @@
+package metaalert
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	metaerror "meta/meta-error"
+	"strings"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+type Kind string
+
+const (
+	KindError Kind = "error"
+	KindPanic Kind = "panic"
+)
+
+// fingerprintFrames 计算指纹时使用的栈帧数量
+const fingerprintFrames = 8
+
+// Alert 一次错误或 panic
+type Alert struct {
+	Kind        Kind
+	Name        string // panic 的协程名
+	Err         error
+	Content     string // 包含模块、节点与错误栈的完整文本
+	Fingerprint string
+	Time        time.Time
+}
+
+// Message 发送给告警渠道的消息
+type Message struct {
+	Title   string
+	Content string
+	Alerts  []*Alert // 汇总消息包含多条告警的样本
+}
+
+// Fingerprint 按错误类型与调用栈计算指纹，不包含行号，避免每次发布后指纹变化
+// 没有调用栈时使用错误信息
+func Fingerprint(kind Kind, name string, err error) string {
+	var builder strings.Builder
+	builder.WriteString(string(kind))
+	builder.WriteString("|")
+	builder.WriteString(name)
+	builder.WriteString("|")
+	builder.WriteString(fmt.Sprint(metaerror.GetErrorType(err)))
+	stack := getStackTrace(err)
+	if len(stack) == 0 {
+		builder.WriteString("|")
+		builder.WriteString(pkgerrors.Cause(err).Error())
+	}
+	for i, frame := range stack {
+		if i >= fingerprintFrames {
+			break
+		}
+		_, _ = fmt.Fprintf(&builder, "|%n@%s", frame, frame)
+	}
+	sum := sha1.Sum([]byte(builder.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// getStackTrace 返回错误链最内层的调用栈
+func getStackTrace(err error) pkgerrors.StackTrace {
+	var stack pkgerrors.StackTrace
+	type causer interface {
+		Cause() error
+	}
+	for err != nil {
+		if tracer, ok := err.(interface{ StackTrace() pkgerrors.StackTrace }); ok {
+			if s := tracer.StackTrace(); len(s) > 0 {
+				stack = s
+			}
+		}
+		c, ok := err.(causer)
+		if !ok {
+			break
+		}
+		next := c.Cause()
+		if next == err {
+			break
+		}
+		err = next
+	}
+	return stack
+}
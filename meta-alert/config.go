@@ -0,0 +1,35 @@
+package metaalert
+
+import (
+	metaemail "meta/meta-email"
+	"time"
+)
+
+type Config struct {
+	Window         time.Duration `yaml:"window"`          // 同一指纹的告警在窗口内只发送一次，默认为 5m
+	DigestInterval time.Duration `yaml:"digest-interval"` // 被抑制告警的汇总间隔，默认为 10m
+	QueueSize      int           `yaml:"queue-size"`      // 待发送告警队列长度，满时丢弃，默认为 256
+
+	FeishuRobots []string         `yaml:"feishu-robots"` // 飞书自定义机器人地址
+	FeishuApp    *FeishuAppConfig `yaml:"feishu-app"`
+	Email        *EmailConfig     `yaml:"email"`
+	Webhooks     []*WebhookConfig `yaml:"webhooks"`
+}
+
+type FeishuAppConfig struct {
+	AppKey  string   `yaml:"app-key"`  // metafeishu 中配置的应用
+	ChatIds []string `yaml:"chat-ids"` // 接收告警的群
+	OpenIds []string `yaml:"open-ids"` // 接收告警的用户
+}
+
+type EmailConfig struct {
+	metaemail.Config `yaml:",inline"`
+	Nickname         string   `yaml:"nickname"` // 发件人名称，默认为模块名
+	To               []string `yaml:"to"`       // 收件人
+}
+
+type WebhookConfig struct {
+	Name    string            `yaml:"name"`
+	Url     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
@@ -0,0 +1,47 @@
+package metaalert
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Sink 告警渠道
+type Sink interface {
+	GetName() string
+	Send(ctx context.Context, message *Message) error
+}
+
+var (
+	sinkMutex sync.RWMutex
+	sinks     = make(map[string]Sink)
+)
+
+// RegisterSink 注册告警渠道，同名渠道会被替换
+func RegisterSink(sink Sink) {
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+	sinks[sink.GetName()] = sink
+}
+
+func UnregisterSink(name string) {
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+	delete(sinks, name)
+}
+
+// GetSinks 按名称排序返回所有告警渠道
+func GetSinks() []Sink {
+	sinkMutex.RLock()
+	defer sinkMutex.RUnlock()
+	result := make([]Sink, 0, len(sinks))
+	for _, sink := range sinks {
+		result = append(result, sink)
+	}
+	sort.Slice(
+		result, func(i, j int) bool {
+			return result[i].GetName() < result[j].GetName()
+		},
+	)
+	return result
+}
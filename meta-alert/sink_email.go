@@ -0,0 +1,42 @@
+package metaalert
+
+import (
+	"context"
+	metaemail "meta/meta-email"
+	metaerror "meta/meta-error"
+)
+
+// EmailSink 邮件告警，每个收件人单独发送
+type EmailSink struct {
+	Name     string
+	Nickname string
+	Config   *metaemail.Config
+	To       []string
+}
+
+func (s *EmailSink) GetName() string {
+	return s.Name
+}
+
+func (s *EmailSink) Send(ctx context.Context, message *Message) error {
+	if s.Config == nil {
+		return metaerror.New("email config is nil")
+	}
+	var lastErr error
+	for _, to := range s.To {
+		err := metaemail.SendEmail(
+			s.Nickname,
+			s.Config.Email,
+			s.Config.Password,
+			s.Config.Host,
+			s.Config.Port,
+			to,
+			message.Title,
+			message.Content,
+		)
+		if err != nil {
+			lastErr = metaerror.Wrap(err, "send alert email failed: %s", to)
+		}
+	}
+	return lastErr
+}
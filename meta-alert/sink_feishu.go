@@ -0,0 +1,58 @@
+package metaalert
+
+import (
+	"context"
+	"fmt"
+	metaerror "meta/meta-error"
+	metafeishu "meta/meta-feishu"
+)
+
+// FeishuRobotSink 飞书群自定义机器人
+type FeishuRobotSink struct {
+	Name     string
+	RobotUrl string
+}
+
+func (s *FeishuRobotSink) GetName() string {
+	return s.Name
+}
+
+func (s *FeishuRobotSink) Send(ctx context.Context, message *Message) error {
+	return metafeishu.SendMessageTextToCustomRobot(s.RobotUrl, formatText(message))
+}
+
+// FeishuAppSink 通过飞书应用发送到群或个人
+type FeishuAppSink struct {
+	Name    string
+	AppKey  string
+	ChatIds []string
+	OpenIds []string
+}
+
+func (s *FeishuAppSink) GetName() string {
+	return s.Name
+}
+
+func (s *FeishuAppSink) Send(ctx context.Context, message *Message) error {
+	feishu := metafeishu.GetSubsystem()
+	if feishu == nil {
+		return metaerror.New("feishu subsystem not found")
+	}
+	text := formatText(message)
+	var lastErr error
+	for _, chatId := range s.ChatIds {
+		if _, err := feishu.SendMessageTextToChat(ctx, s.AppKey, chatId, text); err != nil {
+			lastErr = metaerror.Wrap(err, "send alert to chat failed: %s", chatId)
+		}
+	}
+	for _, openId := range s.OpenIds {
+		if _, err := feishu.SendMessageTextToOpenId(ctx, s.AppKey, openId, text); err != nil {
+			lastErr = metaerror.Wrap(err, "send alert to user failed: %s", openId)
+		}
+	}
+	return lastErr
+}
+
+func formatText(message *Message) string {
+	return fmt.Sprintf("%s\n%s", message.Title, message.Content)
+}
@@ -0,0 +1,84 @@
+package metaalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	metaerror "meta/meta-error"
+	"net/http"
+	"time"
+)
+
+// WebhookSink 以 JSON POST 到任意地址
+type WebhookSink struct {
+	Name    string
+	Url     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+type webhookAlert struct {
+	Kind        Kind      `json:"kind"`
+	Name        string    `json:"name,omitempty"`
+	Fingerprint string    `json:"fingerprint"`
+	Error       string    `json:"error"`
+	Time        time.Time `json:"time"`
+}
+
+type webhookBody struct {
+	Title   string          `json:"title"`
+	Content string          `json:"content"`
+	Alerts  []*webhookAlert `json:"alerts"`
+}
+
+func (s *WebhookSink) GetName() string {
+	return s.Name
+}
+
+func (s *WebhookSink) Send(ctx context.Context, message *Message) error {
+	body := &webhookBody{
+		Title:   message.Title,
+		Content: message.Content,
+	}
+	for _, alert := range message.Alerts {
+		item := &webhookAlert{
+			Kind:        alert.Kind,
+			Name:        alert.Name,
+			Fingerprint: alert.Fingerprint,
+			Time:        alert.Time,
+		}
+		if alert.Err != nil {
+			item.Error = alert.Err.Error()
+		}
+		body.Alerts = append(body.Alerts, item)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return metaerror.Wrap(err, "marshal webhook body failed")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Url, bytes.NewReader(data))
+	if err != nil {
+		return metaerror.Wrap(err, "create webhook request failed")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.Headers {
+		req.Header.Set(key, value)
+	}
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return metaerror.Wrap(err, "send webhook failed: %s", s.Url)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return metaerror.New("webhook status %d: %s", resp.StatusCode, s.Url)
+	}
+	return nil
+}
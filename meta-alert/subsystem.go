@@ -0,0 +1,244 @@
+package metaalert
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"meta/engine"
+	metaconfig "meta/meta-config"
+	metapanic "meta/meta-panic"
+	"meta/subsystem"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Subsystem struct {
+	subsystem.Subsystem
+	GetConfig func() *Config
+
+	throttler *throttler
+	queue     chan *Message
+
+	// 启动前已设置的回调，运行期间由告警回调调用，停止时恢复
+	previousErrorCallback func(err error, format ...any)
+	previousPanicCallback func(name string, err error, format ...any)
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	waitGroup sync.WaitGroup
+}
+
+func GetSubsystem() *Subsystem {
+	if thisSubsystem := engine.GetSubsystem[*Subsystem](); thisSubsystem != nil {
+		return thisSubsystem.(*Subsystem)
+	}
+	return nil
+}
+
+func (s *Subsystem) GetName() string {
+	return "Alert"
+}
+
+// Start 注册配置中的告警渠道并接管 metapanic 的错误与 panic 处理
+func (s *Subsystem) Start() error {
+	config := &Config{}
+	if s.GetConfig != nil {
+		if c := s.GetConfig(); c != nil {
+			config = c
+		}
+	}
+	if config.Window <= 0 {
+		config.Window = 5 * time.Minute
+	}
+	if config.DigestInterval <= 0 {
+		config.DigestInterval = 10 * time.Minute
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 256
+	}
+	registerConfigSinks(config)
+
+	s.throttler = newThrottler(config.Window)
+	s.queue = make(chan *Message, config.QueueSize)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.waitGroup.Add(2)
+	go s.sendLoop()
+	go s.digestLoop(config.DigestInterval)
+
+	s.previousErrorCallback = metapanic.ProcessErrorCallback
+	s.previousPanicCallback = metapanic.ProcessPanicCallback
+	metapanic.ProcessErrorCallback = s.processError
+	metapanic.ProcessPanicCallback = s.processPanic
+	slog.Info("Alert start", "sinks", len(GetSinks()), "window", config.Window)
+	return nil
+}
+
+// Stop 发送最后一次汇总后停止
+func (s *Subsystem) Stop() error {
+	if s.cancel == nil {
+		return nil
+	}
+	metapanic.ProcessErrorCallback = s.previousErrorCallback
+	metapanic.ProcessPanicCallback = s.previousPanicCallback
+	s.cancel()
+	s.waitGroup.Wait()
+	return nil
+}
+
+// processError 已有回调时交给原回调处理，否则按默认方式记录日志
+func (s *Subsystem) processError(err error, format ...any) {
+	var content string
+	if s.previousErrorCallback != nil {
+		s.previousErrorCallback(err, format...)
+		content = metapanic.FormatError(err, format...)
+	} else {
+		content = metapanic.LogError(err, format...)
+	}
+	s.Report(KindError, "", err, content)
+}
+
+func (s *Subsystem) processPanic(name string, err error, format ...any) {
+	var content string
+	if s.previousPanicCallback != nil {
+		s.previousPanicCallback(name, err, format...)
+		content = metapanic.FormatPanic(name, err, format...)
+	} else {
+		content = metapanic.LogPanic(name, err, format...)
+	}
+	s.Report(KindPanic, name, err, content)
+}
+
+func registerConfigSinks(config *Config) {
+	for i, robotUrl := range config.FeishuRobots {
+		RegisterSink(&FeishuRobotSink{Name: fmt.Sprintf("feishu-robot-%d", i), RobotUrl: robotUrl})
+	}
+	if config.FeishuApp != nil {
+		RegisterSink(
+			&FeishuAppSink{
+				Name:    "feishu-app",
+				AppKey:  config.FeishuApp.AppKey,
+				ChatIds: config.FeishuApp.ChatIds,
+				OpenIds: config.FeishuApp.OpenIds,
+			},
+		)
+	}
+	if config.Email != nil {
+		nickname := config.Email.Nickname
+		if nickname == "" {
+			nickname = metaconfig.GetModuleName()
+		}
+		emailConfig := config.Email.Config
+		RegisterSink(&EmailSink{Name: "email", Nickname: nickname, Config: &emailConfig, To: config.Email.To})
+	}
+	for i, webhook := range config.Webhooks {
+		name := webhook.Name
+		if name == "" {
+			name = fmt.Sprintf("webhook-%d", i)
+		}
+		RegisterSink(&WebhookSink{Name: name, Url: webhook.Url, Headers: webhook.Headers})
+	}
+}
+
+// Report 上报一条告警，同一指纹在窗口内重复出现时只计数
+func (s *Subsystem) Report(kind Kind, name string, err error, content string) {
+	if err == nil || s.throttler == nil {
+		return
+	}
+	alert := &Alert{
+		Kind:        kind,
+		Name:        name,
+		Err:         err,
+		Content:     content,
+		Fingerprint: Fingerprint(kind, name, err),
+		Time:        time.Now(),
+	}
+	if !s.throttler.allow(alert) {
+		return
+	}
+	s.enqueue(
+		&Message{
+			Title:   fmt.Sprintf("[%s] %s %s", metaconfig.GetModuleName(), kind, alert.Fingerprint),
+			Content: content,
+			Alerts:  []*Alert{alert},
+		},
+	)
+}
+
+func (s *Subsystem) enqueue(message *Message) {
+	select {
+	case s.queue <- message:
+	default:
+		slog.Warn("Alert queue full, drop message", "title", message.Title)
+	}
+}
+
+func (s *Subsystem) sendLoop() {
+	defer s.waitGroup.Done()
+	for {
+		select {
+		case message := <-s.queue:
+			s.send(message)
+		case <-s.ctx.Done():
+			for {
+				select {
+				case message := <-s.queue:
+					s.send(message)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send 渠道的错误只记录日志，不能再走 metapanic，否则会循环告警
+func (s *Subsystem) send(message *Message) {
+	for _, sink := range GetSinks() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := sink.Send(ctx, message)
+		cancel()
+		if err != nil {
+			slog.Error("Alert send failed", "sink", sink.GetName(), "error", err)
+		}
+	}
+}
+
+func (s *Subsystem) digestLoop(interval time.Duration) {
+	defer s.waitGroup.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.flushDigest()
+		case <-s.ctx.Done():
+			s.flushDigest()
+			return
+		}
+	}
+}
+
+func (s *Subsystem) flushDigest() {
+	items := s.throttler.digest()
+	if len(items) == 0 {
+		return
+	}
+	var builder strings.Builder
+	var alerts []*Alert
+	total := 0
+	for _, item := range items {
+		total += item.Count
+		alerts = append(alerts, item.Sample)
+		_, _ = fmt.Fprintf(
+			&builder, "\n[%s] %s x%d\n%s\n", item.Sample.Kind, item.Sample.Fingerprint, item.Count, item.Sample.Content,
+		)
+	}
+	s.enqueue(
+		&Message{
+			Title:   fmt.Sprintf("[%s] digest: %d suppressed alerts", metaconfig.GetModuleName(), total),
+			Content: strings.TrimPrefix(builder.String(), "\n"),
+			Alerts:  alerts,
+		},
+	)
+}
@@ -0,0 +1,37 @@
+package metaalert
+
+import (
+	"errors"
+	metapanic "meta/meta-panic"
+	"testing"
+)
+
+func TestSubsystemRestoreCallbacks(t *testing.T) {
+	called := 0
+	previous := func(err error, format ...any) {
+		called++
+	}
+	metapanic.ProcessErrorCallback = previous
+	defer func() {
+		metapanic.ProcessErrorCallback = nil
+	}()
+
+	s := &Subsystem{}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	metapanic.ProcessError(errors.New("test"))
+	if called != 1 {
+		t.Errorf("previous callback should be called while alert is running, called %d", called)
+	}
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if metapanic.ProcessPanicCallback != nil {
+		t.Error("panic callback should be restored to nil")
+	}
+	metapanic.ProcessError(errors.New("test"))
+	if called != 2 {
+		t.Errorf("previous callback should be restored after stop, called %d", called)
+	}
+}
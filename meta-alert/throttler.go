@@ -0,0 +1,83 @@
+package metaalert
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type fingerprintState struct {
+	windowStart time.Time
+	lastSeen    time.Time
+	suppressed  int
+	sample      *Alert
+}
+
+// DigestItem 窗口内被抑制的同类告警
+type DigestItem struct {
+	Sample *Alert
+	Count  int
+}
+
+// throttler 同一指纹在窗口内只发送第一条，其余计数后在汇总中发送
+type throttler struct {
+	window time.Duration
+	now    func() time.Time
+
+	mutex  sync.Mutex
+	states map[string]*fingerprintState
+}
+
+func newThrottler(window time.Duration) *throttler {
+	return &throttler{
+		window: window,
+		now:    time.Now,
+		states: make(map[string]*fingerprintState),
+	}
+}
+
+// allow 返回是否需要立即发送
+func (t *throttler) allow(alert *Alert) bool {
+	now := t.now()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	state, ok := t.states[alert.Fingerprint]
+	if !ok || now.Sub(state.windowStart) >= t.window {
+		if !ok {
+			state = &fingerprintState{}
+			t.states[alert.Fingerprint] = state
+		}
+		state.windowStart = now
+		state.lastSeen = now
+		return true
+	}
+	state.lastSeen = now
+	state.suppressed++
+	state.sample = alert
+	return false
+}
+
+// digest 取出所有被抑制的告警并清零，同时清理长时间未出现的指纹
+func (t *throttler) digest() []*DigestItem {
+	now := t.now()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var items []*DigestItem
+	for fingerprint, state := range t.states {
+		if state.suppressed > 0 {
+			items = append(items, &DigestItem{Sample: state.sample, Count: state.suppressed})
+			state.suppressed = 0
+			state.sample = nil
+			continue
+		}
+		if now.Sub(state.lastSeen) >= 2*t.window {
+			delete(t.states, fingerprint)
+		}
+	}
+	sort.Slice(
+		items, func(i, j int) bool {
+			return items[i].Count > items[j].Count
+		},
+	)
+	return items
+}
@@ -0,0 +1,63 @@
+package metaalert
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestThrottler(t *testing.T) {
+	now := time.Unix(1000, 0)
+	throttler := newThrottler(time.Minute)
+	throttler.now = func() time.Time { return now }
+
+	a := &Alert{Fingerprint: "a"}
+	b := &Alert{Fingerprint: "b"}
+	if !throttler.allow(a) || !throttler.allow(b) {
+		t.Fatal("first alert of each fingerprint should be sent")
+	}
+	for i := 0; i < 3; i++ {
+		if throttler.allow(a) {
+			t.Fatal("repeated alert in window should be suppressed")
+		}
+	}
+	items := throttler.digest()
+	if len(items) != 1 || items[0].Count != 3 || items[0].Sample != a {
+		t.Fatalf("unexpected digest: %+v", items)
+	}
+	if len(throttler.digest()) != 0 {
+		t.Fatal("digest should reset suppressed count")
+	}
+
+	now = now.Add(time.Minute)
+	if !throttler.allow(a) {
+		t.Fatal("alert in new window should be sent")
+	}
+	now = now.Add(3 * time.Minute)
+	throttler.digest()
+	if len(throttler.states) != 0 {
+		t.Fatalf("stale fingerprints should be removed: %d", len(throttler.states))
+	}
+}
+
+func newTestError(message string) error {
+	return pkgerrors.New(message)
+}
+
+func TestFingerprint(t *testing.T) {
+	var errs []error
+	for i := 0; i < 2; i++ {
+		errs = append(errs, newTestError("message "+string(rune('a'+i))))
+	}
+	if Fingerprint(KindError, "", errs[0]) != Fingerprint(KindError, "", errs[1]) {
+		t.Error("errors from the same stack should share fingerprint")
+	}
+	if Fingerprint(KindError, "", errs[0]) == Fingerprint(KindError, "", pkgerrors.New("other")) {
+		t.Error("errors from different stacks should differ")
+	}
+	if Fingerprint(KindError, "", errors.New("a")) == Fingerprint(KindError, "", errors.New("b")) {
+		t.Error("errors without stack should use message")
+	}
+}
@@ -2,6 +2,7 @@ package metacmd
 
 import (
 	"log/slog"
+	metaredact "meta/meta-redact"
 	"os/exec"
 	"slices"
 )
@@ -11,7 +12,7 @@ type MetaCmd struct {
 }
 
 func (c *MetaCmd) Run() error {
-	slog.Info("command run", "dir", c.Dir, "path", c.Path, "arg", c.Args)
+	slog.Info("command run", "dir", c.Dir, "path", c.Path, "arg", metaredact.Args(c.Args))
 	return c.Cmd.Run()
 }
 
@@ -22,6 +23,6 @@ func (c *MetaCmd) RunExcludeLog(excludeArg ...string) error {
 			logArg = append(logArg, arg)
 		}
 	}
-	slog.Info("command run", "dir", c.Dir, "path", c.Path, "arg", logArg)
+	slog.Info("command run", "dir", c.Dir, "path", c.Path, "arg", metaredact.Args(logArg))
 	return c.Cmd.Run()
 }
@@ -2,12 +2,13 @@ package metacmd
 
 import (
 	"log/slog"
+	metaredact "meta/meta-redact"
 	"os/exec"
 	"strings"
 )
 
 func Command(name string, arg ...string) *MetaCmd {
-	slog.Info("exec command", "cmd", name+" "+strings.Join(arg, " "))
+	slog.Info("exec command", "cmd", name+" "+strings.Join(metaredact.Args(arg), " "))
 	metaCmd := &MetaCmd{*exec.Command(name, arg...)}
 	// 默认输出一下输入的日志，防止要求输入而卡住
 	logReader := &LogReader{
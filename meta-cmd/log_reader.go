@@ -1,6 +1,9 @@
 package metacmd
 
-import "log/slog"
+import (
+	"log/slog"
+	metaredact "meta/meta-redact"
+)
 
 type LogReader struct {
 	Name string
@@ -8,6 +11,6 @@ type LogReader struct {
 }
 
 func (r *LogReader) Read(p []byte) (n int, err error) {
-	slog.Info("LogReader be called", "name", r.Name, "arg", r.Arg)
+	slog.Info("LogReader be called", "name", r.Name, "arg", metaredact.Args(r.Arg))
 	return len(p), nil
 }
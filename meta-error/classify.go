@@ -0,0 +1,149 @@
+package metaerror
+
+import (
+	"context"
+	"errors"
+	"maps"
+	metaerrorcode "meta/error-code"
+	"net/http"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// 常用的错误分类，可通过 Wrap 附加信息，GetErrorCodeFromError 会映射为对应的错误码
+var (
+	ErrNotFound        error = &MetaError{Code: http.StatusNotFound, Err: errors.New("not found"), Retryable: boolPtr(false)}
+	ErrConflict        error = &MetaError{Code: http.StatusConflict, Err: errors.New("conflict"), Retryable: boolPtr(false)}
+	ErrUnauthorized    error = &MetaError{Code: http.StatusUnauthorized, Err: errors.New("unauthorized"), Retryable: boolPtr(false)}
+	ErrForbidden       error = &MetaError{Code: http.StatusForbidden, Err: errors.New("forbidden"), Retryable: boolPtr(false)}
+	ErrInvalidArgument error = &MetaError{Code: int(metaerrorcode.ParamError), Err: errors.New("invalid argument"), Retryable: boolPtr(false)}
+	ErrRateLimited     error = &MetaError{Code: int(metaerrorcode.TooManyRequests), Err: errors.New("rate limited"), Retryable: boolPtr(true)}
+	ErrUnavailable     error = &MetaError{Code: http.StatusServiceUnavailable, Err: errors.New("unavailable"), Retryable: boolPtr(true)}
+)
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+// annotate 在 err 外层包裹一个继承错误码的 MetaError，用于附加分类信息
+func annotate(err error, apply func(metaError *MetaError)) error {
+	if err == nil {
+		return nil
+	}
+	metaError := &MetaError{
+		Code: GetErrorCodeFromError(err),
+		Err:  err,
+	}
+	if !IsErrorWithStack(err) {
+		metaError.Err = pkgerrors.WithStack(err)
+	}
+	apply(metaError)
+	return metaError
+}
+
+// WithRetryable 标记错误是否可以重试
+func WithRetryable(err error, retryable bool) error {
+	return annotate(
+		err, func(metaError *MetaError) {
+			metaError.Retryable = &retryable
+		},
+	)
+}
+
+// WithUserMessage 附加可以展示给用户的提示，错误本身的内容不会返回给客户端
+func WithUserMessage(err error, message string) error {
+	return annotate(
+		err, func(metaError *MetaError) {
+			metaError.UserMessage = message
+		},
+	)
+}
+
+// WithFields 附加键值对，用于日志与告警，keyValues 按 key, value 交替传入
+func WithFields(err error, keyValues ...any) error {
+	return annotate(
+		err, func(metaError *MetaError) {
+			metaError.Fields = make(map[string]any, len(keyValues)/2)
+			for i := 0; i+1 < len(keyValues); i += 2 {
+				key, ok := keyValues[i].(string)
+				if !ok {
+					continue
+				}
+				metaError.Fields[key] = keyValues[i+1]
+			}
+		},
+	)
+}
+
+// IsRetryable 由最外层显式标记的 MetaError 决定，未标记时按错误码判断
+// context 取消与 4xx 类错误码（429 除外）视为不可重试
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var retryable *bool
+	walkMetaErrors(
+		err, func(metaError *MetaError) bool {
+			retryable = metaError.Retryable
+			return retryable == nil
+		},
+	)
+	if retryable != nil {
+		return *retryable
+	}
+	status := metaerrorcode.GetHttpStatus(GetErrorCodeFromError(err))
+	if status >= 400 && status < 500 && status != http.StatusTooManyRequests {
+		return false
+	}
+	return true
+}
+
+// GetUserMessage 返回最外层的用户提示，没有时返回空字符串
+func GetUserMessage(err error) string {
+	var message string
+	walkMetaErrors(
+		err, func(metaError *MetaError) bool {
+			message = metaError.UserMessage
+			return message == ""
+		},
+	)
+	return message
+}
+
+// GetFields 合并错误链上所有附加的键值对，外层覆盖内层
+func GetFields(err error) map[string]any {
+	var chain []map[string]any
+	walkMetaErrors(
+		err, func(metaError *MetaError) bool {
+			if len(metaError.Fields) > 0 {
+				chain = append(chain, metaError.Fields)
+			}
+			return true
+		},
+	)
+	if len(chain) == 0 {
+		return nil
+	}
+	result := make(map[string]any)
+	for i := len(chain) - 1; i >= 0; i-- {
+		maps.Copy(result, chain[i])
+	}
+	return result
+}
+
+// walkMetaErrors 由外向内遍历错误链上的 MetaError，callback 返回 false 时停止
+func walkMetaErrors(err error, callback func(metaError *MetaError) bool) {
+	for err != nil {
+		var metaError *MetaError
+		if !errors.As(err, &metaError) {
+			return
+		}
+		if !callback(metaError) {
+			return
+		}
+		err = metaError.Err
+	}
+}
@@ -0,0 +1,45 @@
+package metaerror
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	err := Wrap(ErrNotFound, "user not found, id:%d", 1)
+	if !errors.Is(err, ErrNotFound) || GetErrorCodeFromError(err) != http.StatusNotFound {
+		t.Errorf("wrapped sentinel should keep code: %v", err)
+	}
+	if IsRetryable(err) {
+		t.Error("not found should not be retryable")
+	}
+
+	err = WrapCode(ErrNotFound, 2001, "override code")
+	if GetErrorCodeFromError(err) != 2001 || GetErrorCodeFromError(ErrNotFound) != http.StatusNotFound {
+		t.Error("WrapCode should not modify shared sentinel")
+	}
+
+	err = WithUserMessage(WithFields(New("db timeout"), "table", "user", "id", 1), "请稍后再试")
+	if !IsRetryable(err) || GetErrorCodeFromError(err) != 1002 {
+		t.Errorf("unknown error should be retryable: %v", err)
+	}
+	if GetUserMessage(err) != "请稍后再试" {
+		t.Errorf("unexpected user message: %s", GetUserMessage(err))
+	}
+	fields := GetFields(WithFields(err, "id", 2))
+	if fields["table"] != "user" || fields["id"] != 2 {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+
+	if IsRetryable(WithRetryable(ErrNotFound, true)) != true {
+		t.Error("outer retryable flag should win")
+	}
+	if IsRetryable(WithRetryable(ErrRateLimited, false)) {
+		t.Error("outer non retryable flag should win")
+	}
+	if IsRetryable(Wrap(context.Canceled)) {
+		t.Error("canceled context should not be retryable")
+	}
+}
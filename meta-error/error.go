@@ -7,8 +7,11 @@ import (
 )
 
 type MetaError struct {
-	Code int
-	Err  error
+	Code        int
+	Err         error
+	Retryable   *bool          // 是否可以重试，为空时由错误码决定
+	UserMessage string         // 可以展示给用户的提示
+	Fields      map[string]any // 附加的键值对，用于日志与告警
 }
 
 func (e *MetaError) Unwrap() error {
@@ -65,12 +65,8 @@ func WrapCode[T metaerrorcode.Numeric](err error, code T, format ...any) error {
 		return nil
 	}
 	msg := metaformat.Format(format...)
-	var metaErr *MetaError
-	if errors.As(err, &metaErr) {
-		metaErr.Code = int(code)
-		return pkgerrors.WithMessage(metaErr, msg)
-	}
-	if _, ok := err.(interface{ StackTrace() pkgerrors.StackTrace }); ok {
+	// 不修改已有的 MetaError，避免改动到 ErrNotFound 等共享的错误
+	if IsErrorWithStack(err) {
 		return &MetaError{
 			Code: int(code),
 			Err:  pkgerrors.WithMessage(err, msg),
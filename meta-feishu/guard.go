@@ -0,0 +1,51 @@
+package metafeishu
+
+import (
+	metaerror "meta/meta-error"
+	"meta/retry"
+	"net/http"
+)
+
+// GetGuardName 返回应用对应的熔断器与隔离舱名称，可通过 retry.SetBreakerConfig 与 retry.SetBulkheadConfig 单独配置
+func GetGuardName(appKey string) string {
+	return "feishu:" + appKey
+}
+
+// guardHttpClient 飞书 SDK 的所有请求都经过应用的熔断器，配置后也经过隔离舱
+// 飞书不可用时请求立即失败，重试循环不会长时间堆积协程
+type guardHttpClient struct {
+	name   string
+	client *http.Client
+}
+
+func newGuardHttpClient(appKey string) *guardHttpClient {
+	return &guardHttpClient{
+		name:   GetGuardName(appKey),
+		client: &http.Client{},
+	}
+}
+
+func (c *guardHttpClient) Do(req *http.Request) (*http.Response, error) {
+	if bulkhead := retry.GetBulkhead(c.name); bulkhead != nil {
+		release, err := bulkhead.Acquire(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+	done, err := retry.GetBreaker(c.name).Allow()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		done(err)
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		done(metaerror.New("feishu server error status code:%d", resp.StatusCode))
+	} else {
+		done(nil)
+	}
+	return resp, nil
+}
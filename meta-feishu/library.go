@@ -133,7 +133,12 @@ func SendMessageTextToCustomRobot(
 	if err != nil {
 		return metaerror.Wrap(err, "failed to marshal request body")
 	}
-	resp, err := http.Post(robotUrl, "application/json", bytes.NewBuffer(reqBody))
+	request, err := http.NewRequest(http.MethodPost, robotUrl, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return metaerror.Wrap(err, "failed to create request")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	resp, err := newGuardHttpClient("custom-robot").Do(request)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,54 @@
+package metafeishu
+
+import (
+	"errors"
+	metaerror "meta/meta-error"
+	"meta/retry"
+	"time"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+)
+
+// WrapCodeError 保留飞书错误码，供 GetFeishuErrorCode 与 RetryPolicy 判断
+func WrapCodeError(codeError larkcore.CodeError, format ...any) error {
+	return metaerror.Wrap(codeError, format...)
+}
+
+// GetFeishuErrorCode 从错误链中取出飞书错误码
+func GetFeishuErrorCode(err error) (int, bool) {
+	var codeError larkcore.CodeError
+	if errors.As(err, &codeError) {
+		return codeError.Code, true
+	}
+	return 0, false
+}
+
+// IsFeishuErrorRetryable 只有 GetFeishuErrorCodeRetrySleep 中的错误码可以重试
+// 请求本身的错误不重试，避免消息等非幂等请求重复发送
+func IsFeishuErrorRetryable(err error) bool {
+	code, ok := GetFeishuErrorCode(err)
+	return ok && GetFeishuErrorCodeRetrySleep(code) != nil
+}
+
+// GetFeishuErrorDelay 使用错误码对应的等待时间
+func GetFeishuErrorDelay(err error) (time.Duration, bool) {
+	code, ok := GetFeishuErrorCode(err)
+	if !ok {
+		return 0, false
+	}
+	sleep := GetFeishuErrorCodeRetrySleep(code)
+	if sleep == nil {
+		return 0, false
+	}
+	return *sleep, true
+}
+
+// RetryPolicy 飞书接口的重试策略，按错误码决定是否重试与等待时间
+func RetryPolicy(maxAttempts int) *retry.Policy {
+	return &retry.Policy{
+		MaxAttempts:  maxAttempts,
+		InitialDelay: time.Second,
+		RetryIf:      IsFeishuErrorRetryable,
+		DelayFor:     GetFeishuErrorDelay,
+	}
+}
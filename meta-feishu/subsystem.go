@@ -8,7 +8,6 @@ import (
 	metaerror "meta/meta-error"
 	"meta/meta-feishu/variable"
 	metaflag "meta/meta-flag"
-	metalog "meta/meta-log"
 	"meta/retry"
 	"meta/subsystem"
 	"time"
@@ -45,38 +44,28 @@ func (s *Subsystem) Start() error {
 	}
 	s.feishuClients = make(map[string]*lark.Client)
 	for appKey, config := range configs {
-		s.feishuClients[appKey] = startFeishuClient(&config)
+		s.feishuClients[appKey] = startFeishuClient(appKey, &config)
 	}
 	return nil
 }
 
-func IsLogLevelDebug() bool {
-	if IsSwitchDebugToInfo() {
-		return true
-	}
-	return metalog.IsLogDebug()
-}
-
 func IsLogReqAtDebug() bool {
 	//return metaflag.IsDebug()
 	return true
 }
 
-func startFeishuClient(config *AppConfig) *lark.Client {
+func startFeishuClient(appKey string, config *AppConfig) *lark.Client {
 	if metaflag.IsDebug() {
 		slog.Info("startFeishuClient", "config", config)
 	}
 
-	logLevel := larkcore.LogLevelInfo
-	if IsLogLevelDebug() {
-		logLevel = larkcore.LogLevelDebug
-	}
-
+	// SDK 始终输出 Debug 日志，由 Logger 按 feishu 组件等级过滤
 	cli := lark.NewClient(
 		config.AppId, config.AppSecret,
-		lark.WithLogLevel(logLevel),
+		lark.WithLogLevel(larkcore.LogLevelDebug),
 		lark.WithLogReqAtDebug(IsLogReqAtDebug()),
 		lark.WithLogger(NewLogger()),
+		lark.WithHttpClient(newGuardHttpClient(appKey)),
 	)
 	return cli
 }
@@ -155,30 +144,26 @@ func (s *Subsystem) SendMessageByReq(
 	if client == nil {
 		return nil, metaerror.New("feishu client is nil, appKey:%s", appKey)
 	}
-	var err error
-	var resp *larkim.CreateMessageResp
-	retryErr := retry.TryRetryDynamicSleep(
-		"Feishu Message Send", 6, func(i int) *time.Duration {
-			resp, err = client.Im.Message.Create(ctx, req)
+	resp, result := retry.DoValue(
+		ctx, "Feishu Message Send", RetryPolicy(6),
+		func(ctx context.Context, attempt int) (*larkim.CreateMessageResp, error) {
+			resp, err := client.Im.Message.Create(ctx, req)
 			if err != nil {
-				err = metaerror.Wrap(err, "failed to send message, appKey:%s", appKey)
-				return nil
+				return nil, metaerror.Wrap(err, "failed to send message, appKey:%s", appKey)
 			}
 			if !resp.Success() {
-				err = metaerror.WrapFeishu(
-					resp,
+				return resp, WrapCodeError(
+					resp.CodeError,
 					"send message failed, appKey:%s, req:%s",
 					appKey,
 					s.GetCreateMessageReqLog(req),
 				)
-				return GetFeishuErrorCodeRetrySleep(resp.Code)
 			}
-			return nil
+			return resp, nil
 		},
 	)
-	err = metaerror.Join(err, retryErr)
-	if err != nil {
-		return nil, err
+	if result.Err != nil {
+		return nil, result.Err
 	}
 	return resp.Data.MessageId, nil
 }
@@ -518,6 +503,10 @@ func (s *Subsystem) UpdateMessageCardTemplate(
 			resp, err = client.Im.Message.Patch(ctx, req)
 			if err != nil {
 				err = metaerror.Wrap(err, "failed to update message, messageId:%s templateId:%s", messageId, templateId)
+				// 熔断或并发已满时不再等待重试
+				if !metaerror.IsRetryable(err) {
+					return nil
+				}
 				sleep := time.Second * 30
 				return &sleep
 			}
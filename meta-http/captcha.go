@@ -0,0 +1,41 @@
+package metahttp
+
+import (
+	"log/slog"
+	"meta/captcha"
+	metaerrorcode "meta/error-code"
+	metaresponse "meta/meta-response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CaptchaConfig struct {
+	Verifier  captcha.Verifier // captcha.NewTurnstileVerifier 或 captcha.NewRecaptchaVerifier
+	Header    string           // 读取 token 的请求头，优先于表单字段
+	FormField string           // 读取 token 的表单或查询字段，默认为 captcha
+}
+
+// CaptchaMiddleware 校验人机验证 token，失败时返回 CaptchaError
+// 可作为 Route.Middlewares 只作用于指定路由
+func CaptchaMiddleware(config *CaptchaConfig) gin.HandlerFunc {
+	if config.FormField == "" && config.Header == "" {
+		config.FormField = "captcha"
+	}
+	return func(c *gin.Context) {
+		token := ""
+		if config.Header != "" {
+			token = c.GetHeader(config.Header)
+		}
+		if token == "" && config.FormField != "" {
+			token = c.Request.FormValue(config.FormField)
+		}
+		err := captcha.VerifyToken(c, config.Verifier, token, c.ClientIP())
+		if err != nil {
+			slog.InfoContext(c, "captcha verify failed", "path", c.Request.URL.Path, "err", err)
+			metaresponse.NewResponse(c, metaerrorcode.CaptchaError)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
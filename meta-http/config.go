@@ -0,0 +1,49 @@
+package metahttp
+
+import (
+	metaconfig "meta/meta-config"
+	"time"
+)
+
+type Config struct {
+	ReadTimeout       time.Duration `yaml:"read-timeout"`        // 读取整个请求的超时
+	ReadHeaderTimeout time.Duration `yaml:"read-header-timeout"` // 读取请求头的超时
+	WriteTimeout      time.Duration `yaml:"write-timeout"`       // 写出响应的超时，默认不限制，设置后超时的大文件下载与 SSE 等长响应会被中断
+	IdleTimeout       time.Duration `yaml:"idle-timeout"`        // Keep-Alive 空闲超时
+	ShutdownTimeout   time.Duration `yaml:"shutdown-timeout"`    // 停止时等待请求处理完成的超时
+
+	// 可信代理的 IP 或网段，配置后只有来自可信代理的请求才使用 X-Forwarded-For 等头获取客户端 IP
+	// 为空时保持 gin 的默认行为信任所有代理，客户端可伪造 X-Forwarded-For，按 IP 限流时建议配置
+	TrustedProxies []string `yaml:"trusted-proxies"`
+
+	MetricsPath string `yaml:"metrics-path"` // Prometheus 指标路径，为空则不提供
+
+	OpenApiPath    string `yaml:"openapi-path"`    // OpenAPI 文档路径，为空则不提供
+	SwaggerUiPath  string `yaml:"swagger-ui-path"` // Swagger UI 页面路径，为空则不提供
+	OpenApiTitle   string `yaml:"openapi-title"`   // 文档标题，默认为模块名
+	OpenApiVersion string `yaml:"openapi-version"` // 文档版本
+
+	// Swagger UI 静态资源地址，需提供 swagger-ui.css 与 swagger-ui-bundle.js，默认为固定版本的 unpkg 地址
+	SwaggerUiAssetUrl string `yaml:"swagger-ui-asset-url"`
+}
+
+func (c *Config) setDefault() {
+	if c.ReadTimeout <= 0 {
+		c.ReadTimeout = 60 * time.Second
+	}
+	if c.ReadHeaderTimeout <= 0 {
+		c.ReadHeaderTimeout = 10 * time.Second
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = 120 * time.Second
+	}
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = 10 * time.Second
+	}
+	if c.OpenApiTitle == "" {
+		c.OpenApiTitle = metaconfig.GetModuleName()
+	}
+	if c.OpenApiVersion == "" {
+		c.OpenApiVersion = "1.0.0"
+	}
+}
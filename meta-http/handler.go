@@ -0,0 +1,156 @@
+package metahttp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	metaerrorcode "meta/error-code"
+	metaerror "meta/meta-error"
+	metaresponse "meta/meta-response"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+var (
+	ginContextType = reflect.TypeOf((*gin.Context)(nil))
+	errorType      = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// createHandler 根据控制器方法签名生成处理函数，支持：
+//
+//	func(ctx *gin.Context)
+//	func(ctx *gin.Context, req *Req) error
+//	func(ctx *gin.Context, req *Req) (*Resp, error)
+//	func(ctx *gin.Context) (*Resp, error)
+//
+// 带 req 参数时依次绑定查询参数(form)、请求体(json/form)与路径参数(uri)，
+// 再按 binding 标签校验，结果通过 metaresponse.NewResponseError 返回
+// 不支持的签名返回 nil
+func createHandler(method reflect.Value) gin.HandlerFunc {
+	methodType := method.Type()
+	if methodType.NumIn() < 1 || methodType.NumIn() > 2 || methodType.In(0) != ginContextType {
+		return nil
+	}
+	if methodType.NumIn() == 1 && methodType.NumOut() == 0 {
+		return func(c *gin.Context) {
+			method.Call([]reflect.Value{reflect.ValueOf(c)})
+		}
+	}
+
+	var reqType reflect.Type
+	if methodType.NumIn() == 2 {
+		reqType = methodType.In(1)
+		if reqType.Kind() != reflect.Ptr || reqType.Elem().Kind() != reflect.Struct {
+			return nil
+		}
+	}
+	switch methodType.NumOut() {
+	case 1:
+		if methodType.Out(0) != errorType {
+			return nil
+		}
+	case 2:
+		if methodType.Out(1) != errorType {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return func(c *gin.Context) {
+		args := []reflect.Value{reflect.ValueOf(c)}
+		if reqType != nil {
+			req := reflect.New(reqType.Elem())
+			if err := BindRequest(c, req.Interface()); err != nil {
+				metaresponse.NewResponseError(c, metaerror.WrapCode(err, metaerrorcode.ParamError, "bind request failed"))
+				return
+			}
+			args = append(args, req)
+		}
+		results := method.Call(args)
+		errValue := results[len(results)-1]
+		var err error
+		if !errValue.IsNil() {
+			err = errValue.Interface().(error)
+		}
+		if len(results) == 1 || isNilValue(results[0]) {
+			metaresponse.NewResponseError(c, err)
+			return
+		}
+		metaresponse.NewResponseError(c, err, results[0].Interface())
+	}
+}
+
+// BindRequest 将查询参数、请求体与路径参数绑定到 req，并按 binding 标签校验
+// 路径参数优先级最高，其次为请求体，最后为查询参数
+func BindRequest(c *gin.Context, req any) error {
+	if err := binding.MapFormWithTag(req, c.Request.URL.Query(), "form"); err != nil {
+		return metaerror.Wrap(err, "bind query failed")
+	}
+	if hasBody(c.Request) {
+		switch c.ContentType() {
+		case binding.MIMEJSON:
+			if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil && !errors.Is(err, io.EOF) {
+				return metaerror.Wrap(err, "bind json body failed")
+			}
+		case binding.MIMEPOSTForm:
+			if err := c.Request.ParseForm(); err != nil {
+				return metaerror.Wrap(err, "parse form failed")
+			}
+			if err := binding.MapFormWithTag(req, c.Request.PostForm, "form"); err != nil {
+				return metaerror.Wrap(err, "bind form body failed")
+			}
+		case binding.MIMEMultipartPOSTForm:
+			if err := c.Request.ParseMultipartForm(32 << 20); err != nil {
+				return metaerror.Wrap(err, "parse multipart form failed")
+			}
+			if err := binding.MapFormWithTag(req, c.Request.MultipartForm.Value, "form"); err != nil {
+				return metaerror.Wrap(err, "bind multipart form failed")
+			}
+		}
+	}
+	if len(c.Params) > 0 {
+		params := make(map[string][]string, len(c.Params))
+		for _, param := range c.Params {
+			params[param.Key] = []string{param.Value}
+		}
+		if err := binding.MapFormWithTag(req, params, "uri"); err != nil {
+			return metaerror.Wrap(err, "bind uri failed")
+		}
+	}
+	if binding.Validator == nil {
+		return nil
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return metaerror.Wrap(err, "validate request failed")
+	}
+	return nil
+}
+
+func hasBody(request *http.Request) bool {
+	return request.Body != nil && request.Body != http.NoBody && request.ContentLength != 0
+}
+
+func isNilValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+// getHandlerTypes 返回类型化处理函数的请求结构体与响应数据类型
+func getHandlerTypes(methodType reflect.Type) (reflect.Type, reflect.Type) {
+	var reqType, respType reflect.Type
+	if methodType.NumIn() == 2 {
+		reqType = methodType.In(1).Elem()
+	}
+	if methodType.NumOut() == 2 {
+		respType = methodType.Out(0)
+	}
+	return reqType, respType
+}
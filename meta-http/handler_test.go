@@ -0,0 +1,110 @@
+package metahttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testUserReq struct {
+	Id    int    `uri:"id"`
+	Page  int    `form:"page"`
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"omitempty,email"`
+}
+
+type testUserResp struct {
+	Id   int    `json:"id"`
+	Page int    `json:"page"`
+	Name string `json:"name"`
+}
+
+type testUserController struct {
+}
+
+func (c *testUserController) PostUser(ctx *gin.Context, req *testUserReq) (*testUserResp, error) {
+	return &testUserResp{Id: req.Id, Page: req.Page, Name: req.Name}, nil
+}
+
+func (c *testUserController) DeleteUser(ctx *gin.Context, req *testUserReq) error {
+	return errors.New("delete failed")
+}
+
+func TestCreateHandlerTyped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	controller := &testUserController{}
+	typedHandler := createHandler(reflect.ValueOf(controller).MethodByName("PostUser"))
+	r.POST("/user/:id", typedHandler)
+	r.DELETE("/user/:id", createHandler(reflect.ValueOf(controller).MethodByName("DeleteUser")))
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		code   int
+		data   *testUserResp
+	}{
+		{
+			name:   "bind all sources",
+			method: http.MethodPost,
+			body:   `{"name":"meta"}`,
+			code:   0,
+			data:   &testUserResp{Id: 7, Page: 2, Name: "meta"},
+		},
+		{
+			name:   "validate required",
+			method: http.MethodPost,
+			body:   `{}`,
+			code:   1004,
+		},
+		{
+			name:   "validate email",
+			method: http.MethodPost,
+			body:   `{"name":"meta","email":"invalid"}`,
+			code:   1004,
+		},
+		{
+			name:   "error only",
+			method: http.MethodDelete,
+			body:   `{"name":"meta"}`,
+			code:   1002,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				request := httptest.NewRequest(tt.method, "/user/7?page=2", strings.NewReader(tt.body))
+				request.Header.Set("Content-Type", "application/json")
+				recorder := httptest.NewRecorder()
+				r.ServeHTTP(recorder, request)
+
+				var response struct {
+					Code int           `json:"code"`
+					Data *testUserResp `json:"data"`
+				}
+				if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Unmarshal response failed: %v, body: %s", err, recorder.Body.String())
+				}
+				if response.Code != tt.code {
+					t.Errorf("Expected code %d, got %d", tt.code, response.Code)
+				}
+				if tt.data != nil && (response.Data == nil || *response.Data != *tt.data) {
+					t.Errorf("Expected data %+v, got %+v", tt.data, response.Data)
+				}
+			},
+		)
+	}
+}
+
+func TestCreateHandlerUnsupported(t *testing.T) {
+	if handler := createHandler(reflect.ValueOf(func(ctx *gin.Context, id int) {})); handler != nil {
+		t.Errorf("Expected unsupported signature to return nil handler")
+	}
+}
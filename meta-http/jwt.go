@@ -0,0 +1,187 @@
+package metahttp
+
+import (
+	"context"
+	"log/slog"
+	"meta/auth"
+	metaresponse "meta/meta-response"
+	"meta/metaroutine"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ContextKeyJwtClaims  = "meta-jwt-claims"
+	ContextKeyJwtSubject = "meta-jwt-subject"
+)
+
+// TokenSource 从请求中读取 token，不存在时返回空字符串
+type TokenSource func(c *gin.Context) string
+
+func TokenFromHeader(name string, prefix string) TokenSource {
+	return func(c *gin.Context) string {
+		value := c.GetHeader(name)
+		if prefix == "" {
+			return value
+		}
+		if len(value) > len(prefix) && strings.EqualFold(value[:len(prefix)], prefix) {
+			return strings.TrimSpace(value[len(prefix):])
+		}
+		return ""
+	}
+}
+
+func TokenFromCookie(name string) TokenSource {
+	return func(c *gin.Context) string {
+		value, err := c.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return value
+	}
+}
+
+type JwtAuthConfig struct {
+	KeySet       *auth.KeySet
+	TokenSources []TokenSource      // 按顺序读取，默认 Authorization: Bearer 与 X-Token
+	NewClaims    func() jwt.Claims  // 默认为 jwt.MapClaims
+	Options      []jwt.ParserOption // 额外的校验参数，如 jwt.WithIssuer
+
+	JwksFile            string        // 校验密钥的 JWKS 文件，会与 KeySet 中的签名密钥合并
+	JwksRefreshInterval time.Duration // JWKS 文件重新加载间隔，0 为不重新加载
+
+	// Validate 解析成功后的额外校验，如检查吊销列表
+	Validate func(c *gin.Context, claims jwt.Claims) error
+}
+
+// NewJwtAuthMiddleware 创建必须鉴权与可选鉴权两个中间件
+// 可选鉴权在 token 缺失或无效时按匿名处理，ctx 结束后停止重新加载 JWKS
+func NewJwtAuthMiddleware(ctx context.Context, config *JwtAuthConfig) (gin.HandlerFunc, gin.HandlerFunc, error) {
+	if config.KeySet == nil {
+		config.KeySet = auth.NewKeySet()
+	}
+	if len(config.TokenSources) == 0 {
+		config.TokenSources = []TokenSource{
+			TokenFromHeader("Authorization", "Bearer "),
+			TokenFromHeader("X-Token", ""),
+		}
+	}
+	if config.NewClaims == nil {
+		config.NewClaims = func() jwt.Claims {
+			return jwt.MapClaims{}
+		}
+	}
+	if config.JwksFile != "" {
+		if err := reloadJwks(config); err != nil {
+			return nil, nil, err
+		}
+		if config.JwksRefreshInterval > 0 {
+			metaroutine.SafeGoWithRestart(
+				"Jwks reload",
+				func() error {
+					ticker := time.NewTicker(config.JwksRefreshInterval)
+					defer ticker.Stop()
+					for {
+						select {
+						case <-ctx.Done():
+							return nil
+						case <-ticker.C:
+							if err := reloadJwks(config); err != nil {
+								slog.Error("reload jwks failed", "file", config.JwksFile, "err", err)
+							}
+						}
+					}
+				},
+			)
+		}
+	}
+	return newJwtAuthHandler(config, true), newJwtAuthHandler(config, false), nil
+}
+
+// UseJwtAuth 使用 JWT 鉴权作为 AutoRegisterRoute 的鉴权中间件
+func UseJwtAuth(ctx context.Context, config *JwtAuthConfig) error {
+	required, optional, err := NewJwtAuthMiddleware(ctx, config)
+	if err != nil {
+		return err
+	}
+	AuthMiddleware = required
+	AuthMiddlewareOptional = optional
+	return nil
+}
+
+func newJwtAuthHandler(config *JwtAuthConfig, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := ""
+		for _, source := range config.TokenSources {
+			if token = source(c); token != "" {
+				break
+			}
+		}
+		if token == "" {
+			if required {
+				abortUnauthorized(c)
+				return
+			}
+			c.Next()
+			return
+		}
+		claims := config.NewClaims()
+		err := config.KeySet.ValidateContext(c.Request.Context(), token, claims, config.Options...)
+		if err == nil && config.Validate != nil {
+			err = config.Validate(c, claims)
+		}
+		if err != nil {
+			slog.InfoContext(c, "jwt validate failed", "path", c.Request.URL.Path, "err", err)
+			if required {
+				abortUnauthorized(c)
+				return
+			}
+			c.Next()
+			return
+		}
+		c.Set(ContextKeyJwtClaims, claims)
+		if subject, err := claims.GetSubject(); err == nil && subject != "" {
+			c.Set(ContextKeyJwtSubject, subject)
+		}
+		c.Next()
+	}
+}
+
+func abortUnauthorized(c *gin.Context) {
+	metaresponse.NewResponse(c, http.StatusUnauthorized)
+	c.Abort()
+}
+
+func reloadJwks(config *JwtAuthConfig) error {
+	keys, err := auth.LoadJwksFile(config.JwksFile)
+	if err != nil {
+		return err
+	}
+	config.KeySet.ReplaceVerifyKeys(keys)
+	return nil
+}
+
+// GetJwtClaims 返回鉴权中间件写入的 claims，未鉴权时返回 nil
+func GetJwtClaims(c *gin.Context) jwt.Claims {
+	value, ok := c.Get(ContextKeyJwtClaims)
+	if !ok {
+		return nil
+	}
+	claims, _ := value.(jwt.Claims)
+	return claims
+}
+
+// GetJwtClaimsAs 按具体类型返回 claims
+func GetJwtClaimsAs[T jwt.Claims](c *gin.Context) (T, bool) {
+	claims, ok := GetJwtClaims(c).(T)
+	return claims, ok
+}
+
+// GetJwtSubject 返回 claims 中的 sub，未鉴权时返回空字符串
+func GetJwtSubject(c *gin.Context) string {
+	return c.GetString(ContextKeyJwtSubject)
+}
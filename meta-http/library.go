@@ -1,14 +1,22 @@
 package metahttp
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"io"
 	metaerror "meta/meta-error"
 	metapanic "meta/meta-panic"
+	metatracing "meta/meta-tracing"
 	"meta/retry"
 	"net/http"
+	"net/url"
 	"path"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func UrlJoin(url string, paths ...string) string {
@@ -23,10 +31,30 @@ func UrlJoin(url string, paths ...string) string {
 }
 
 func SendRequest(client *http.Client, method, url string, body io.Reader) (int, []byte, error) {
-	fileListRequest, err := http.NewRequest(method, url, body)
+	return SendRequestContext(context.Background(), client, method, url, body)
+}
+
+// SendRequestContext 请求会携带 ctx 中的请求ID与 traceparent
+func SendRequestContext(
+	ctx context.Context,
+	client *http.Client,
+	method, url string,
+	body io.Reader,
+) (status int, responseBody []byte, err error) {
+	ctx, span := metatracing.StartSpan(
+		ctx, "HTTP "+method, trace.SpanKindClient,
+		attribute.String("http.request.method", method),
+		attribute.String("url.full", url),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		metatracing.EndSpan(span, err)
+	}()
+	fileListRequest, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return -1, nil, metaerror.Wrap(err, "failed to create file list request")
 	}
+	InjectTraceHeader(ctx, fileListRequest.Header)
 	fileListResp, err := client.Do(fileListRequest)
 	if err != nil {
 		return -1, nil, err
@@ -37,10 +65,20 @@ func SendRequest(client *http.Client, method, url string, body io.Reader) (int,
 			metapanic.ProcessError(err)
 		}
 	}(fileListResp.Body)
-	responseBody, err := io.ReadAll(fileListResp.Body)
+	responseBody, err = io.ReadAll(fileListResp.Body)
 	return fileListResp.StatusCode, responseBody, err
 }
 
+// GetGuardName 返回请求地址对应的熔断器与隔离舱名称，按 host 区分
+func GetGuardName(rawUrl string) string {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil || parsed.Host == "" {
+		return "http:" + rawUrl
+	}
+	return "http:" + parsed.Host
+}
+
+// SendRequestRetry 固定间隔重试，checkStatus 时非 200 的状态码也会重试
 func SendRequestRetry(
 	client *http.Client,
 	name string,
@@ -51,47 +89,125 @@ func SendRequestRetry(
 	body io.Reader,
 	checkStatus bool,
 ) (int, []byte, error) {
+	policy := &retry.Policy{
+		MaxAttempts:  maxCount,
+		InitialDelay: sleep,
+		Multiplier:   1,
+		Jitter:       retry.JitterNone,
+	}
+	if checkStatus {
+		policy.RetryIf = retry.RetryIfAny(metaerror.IsRetryable, retry.RetryOnStatus())
+	}
+	return SendRequestRetryContext(context.Background(), client, name, policy, method, url, headers, body, checkStatus)
+}
+
+// SendRequestRetryContext 按策略重试，每次尝试都在目标 host 的熔断器与隔离舱保护下执行
+// body 会先完整读取，每次尝试使用新的 Reader
+// checkStatus 时非 200 的状态码返回 retry.StatusError，默认只重试 retry.RetryableStatuses 中的状态码并遵循 Retry-After
+func SendRequestRetryContext(
+	ctx context.Context,
+	client *http.Client,
+	name string,
+	policy *retry.Policy,
+	method, url string,
+	headers map[string]string,
+	body io.Reader,
+	checkStatus bool,
+) (int, []byte, error) {
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(body)
+		if err != nil {
+			return 0, nil, metaerror.Wrap(err, "read request body failed")
+		}
+	}
+	p := retry.Policy{}
+	if policy != nil {
+		p = *policy
+	}
+	if p.RetryIf == nil {
+		p.RetryIf = getRetryIf
+	}
+	if p.DelayFor == nil {
+		p.DelayFor = retry.DelayForRetryAfter
+	}
+	guardName := GetGuardName(url)
 	var status int
 	var responseBody []byte
-	var finalErr error
-	_ = retry.TryRetrySleep(
-		name, maxCount, sleep, func(i int) bool {
-			fileListRequest, err := http.NewRequest(method, url, body)
-			if err != nil {
-				finalErr = err
-				return true
+	result := retry.Do(
+		ctx, name, &p, func(ctx context.Context, attempt int) error {
+			var requestErr error
+			var header http.Header
+			executed := false
+			guardErr := retry.Protect(
+				ctx, guardName, func() error {
+					executed = true
+					var reader io.Reader
+					if bodyBytes != nil {
+						reader = bytes.NewReader(bodyBytes)
+					}
+					status, header, responseBody, requestErr = sendRequestOnce(ctx, client, method, url, headers, reader)
+					if requestErr != nil {
+						return requestErr
+					}
+					if status >= http.StatusInternalServerError {
+						return retry.NewStatusError(status, header)
+					}
+					return nil
+				},
+			)
+			if !executed {
+				// 熔断或并发已满，请求未发出
+				status, responseBody = 0, nil
+				return guardErr
 			}
-			if len(headers) > 0 {
-				for key, value := range headers {
-					fileListRequest.Header.Set(key, value)
-				}
+			if requestErr != nil {
+				return requestErr
 			}
-			fileListResp, err := client.Do(fileListRequest)
-			if err != nil {
-				finalErr = err
-				return false
+			if checkStatus && status != http.StatusOK {
+				responseBody = nil
+				return metaerror.Wrap(retry.NewStatusError(status, header), "method:%s, url:%s", method, url)
 			}
-			defer func(Body io.ReadCloser) {
-				err := Body.Close()
-				if err != nil {
-					metapanic.ProcessError(err)
-				}
-			}(fileListResp.Body)
-			status = fileListResp.StatusCode
-			if checkStatus {
-				if status != http.StatusOK {
-					finalErr = metaerror.New(
-						"unexpected status code:%d, method:%s, url:%s",
-						fileListResp.StatusCode,
-						method,
-						url,
-					)
-					return false
-				}
-			}
-			responseBody, finalErr = io.ReadAll(fileListResp.Body)
-			return true
+			return nil
 		},
 	)
-	return status, responseBody, finalErr
+	return status, responseBody, result.Err
+}
+
+// getRetryIf 请求错误按 metaerror.IsRetryable 判断，状态码错误只重试 RetryableStatuses
+func getRetryIf(err error) bool {
+	var statusError *retry.StatusError
+	if errors.As(err, &statusError) {
+		return retry.RetryOnStatus()(err)
+	}
+	return metaerror.IsRetryable(err)
+}
+
+func sendRequestOnce(
+	ctx context.Context,
+	client *http.Client,
+	method, url string,
+	headers map[string]string,
+	body io.Reader,
+) (int, http.Header, []byte, error) {
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			metapanic.ProcessError(err)
+		}
+	}(response.Body)
+	responseBody, err := io.ReadAll(response.Body)
+	return response.StatusCode, response.Header, responseBody, err
 }
@@ -0,0 +1,56 @@
+package metahttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"meta/retry"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendRequestRetryContext(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				if len(bodies) < 3 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				_, _ = w.Write([]byte("ok"))
+			},
+		),
+	)
+	defer server.Close()
+
+	policy := &retry.Policy{MaxAttempts: 5, InitialDelay: time.Millisecond}
+	status, body, err := SendRequestRetryContext(
+		context.Background(), server.Client(), "test", policy,
+		http.MethodPost, server.URL, nil, strings.NewReader("payload"), true,
+	)
+	if err != nil || status != http.StatusOK || string(body) != "ok" {
+		t.Fatalf("unexpected result: %d %s %v", status, body, err)
+	}
+	if len(bodies) != 3 || bodies[1] != "payload" || bodies[2] != "payload" {
+		t.Fatalf("every attempt should send the full body: %q", bodies)
+	}
+
+	bodies = nil
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	status, _, err = SendRequestRetryContext(
+		context.Background(), notFound.Client(), "test", policy,
+		http.MethodGet, notFound.URL, nil, nil, true,
+	)
+	var statusError *retry.StatusError
+	if status != http.StatusNotFound || !errors.As(err, &statusError) {
+		t.Fatalf("404 should not be retried: %d %v", status, err)
+	}
+}
@@ -0,0 +1,47 @@
+package metahttp
+
+import (
+	"log/slog"
+	metaerrorcode "meta/error-code"
+	metaerror "meta/meta-error"
+	metalog "meta/meta-log"
+	metaresponse "meta/meta-response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type logLevelRequest struct {
+	Name  string `json:"name"`  // 组件名称，为空或 root 时修改根等级
+	Level string `json:"level"` // 为空时恢复跟随根等级
+}
+
+// LogLevelHandler 返回各组件的生效日志等级，需由使用方挂载在受保护的管理路由下
+func LogLevelHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, metalog.GetLevels())
+}
+
+// SetLogLevelHandler 运行时修改组件的日志等级，需由使用方挂载在受保护的管理路由下
+func SetLogLevelHandler(c *gin.Context) {
+	var request logLevelRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		metaresponse.NewResponseError(c, metaerror.WrapCode(err, metaerrorcode.ParamError))
+		return
+	}
+	if request.Level == "" {
+		if request.Name == "" || request.Name == metalog.LoggerRoot {
+			metaresponse.NewResponseError(c, metaerror.NewCode(metaerrorcode.ParamError, "root level is required"))
+			return
+		}
+		metalog.ResetLevel(request.Name)
+	} else {
+		level, err := metalog.ParseLevel(request.Level)
+		if err != nil {
+			metaresponse.NewResponseError(c, metaerror.WrapCode(err, metaerrorcode.ParamError))
+			return
+		}
+		metalog.SetLevel(request.Name, level)
+	}
+	slog.InfoContext(c, "Log level changed", "name", request.Name, "level", request.Level)
+	c.JSON(http.StatusOK, metalog.GetLevels())
+}
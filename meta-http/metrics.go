@@ -0,0 +1,42 @@
+package metahttp
+
+import (
+	metametrics "meta/meta-metrics"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	httpRequestsTotal = metametrics.NewCounterVec(
+		"http_requests_total", "Total number of HTTP requests.",
+		"method", "route", "status",
+	)
+	httpRequestDuration = metametrics.NewHistogramVec(
+		"http_request_duration_seconds", "HTTP request latency.", nil,
+		"method", "route", "status",
+	)
+	httpRequestsInFlight = metametrics.NewGauge(
+		"http_requests_in_flight", "Number of HTTP requests being served.",
+	)
+)
+
+// MetricsMiddleware 按路由模板与状态码统计请求数与耗时，未匹配的路由统一记为 unmatched
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		httpRequestsInFlight.Inc()
+		defer httpRequestsInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequestsTotal.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,485 @@
+package metahttp
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	metaerrorcode "meta/error-code"
+	metaresponse "meta/meta-response"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenApi 文档结构，仅包含生成时用到的字段
+type OpenApi struct {
+	OpenApi    string                                  `json:"openapi"`
+	Info       OpenApiInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenApiOperation `json:"paths"`
+	Components OpenApiComponents                       `json:"components"`
+}
+
+type OpenApiInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type OpenApiComponents struct {
+	Schemas         map[string]*OpenApiSchema         `json:"schemas"`
+	SecuritySchemes map[string]*OpenApiSecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type OpenApiSecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+type OpenApiOperation struct {
+	OperationId string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	Parameters  []*OpenApiParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenApiRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenApiResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+}
+
+type OpenApiParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *OpenApiSchema `json:"schema"`
+}
+
+type OpenApiRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenApiMediaType `json:"content"`
+}
+
+type OpenApiResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenApiMediaType `json:"content,omitempty"`
+}
+
+type OpenApiMediaType struct {
+	Schema *OpenApiSchema `json:"schema"`
+}
+
+type OpenApiSchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Items                *OpenApiSchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenApiSchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenApiSchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+}
+
+const (
+	openApiSchemaErrorCode     = "ErrorCode"
+	openApiSchemaErrorResponse = "ErrorResponse"
+	openApiSecurityBearer      = "bearerAuth"
+)
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	routeParamRegex = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+)
+
+// GenerateOpenApi 根据已注册的路由生成 OpenAPI 3 文档
+func GenerateOpenApi(info OpenApiInfo) *OpenApi {
+	builder := &openApiBuilder{
+		schemas: make(map[string]*OpenApiSchema),
+		names:   make(map[reflect.Type]string),
+	}
+	builder.schemas[openApiSchemaErrorCode] = getErrorCodeSchema()
+	builder.schemas[openApiSchemaErrorResponse] = getResponseSchema(nil)
+
+	doc := &OpenApi{
+		OpenApi: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]map[string]*OpenApiOperation),
+		Components: OpenApiComponents{
+			Schemas: builder.schemas,
+			SecuritySchemes: map[string]*OpenApiSecurityScheme{
+				openApiSecurityBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+	for _, record := range GetRouteRecords() {
+		path := routeParamRegex.ReplaceAllString(record.Path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*OpenApiOperation)
+		}
+		doc.Paths[path][strings.ToLower(record.Method)] = builder.buildOperation(record)
+	}
+	return doc
+}
+
+type openApiBuilder struct {
+	schemas map[string]*OpenApiSchema
+	names   map[reflect.Type]string
+}
+
+func (b *openApiBuilder) buildOperation(record *RouteRecord) *OpenApiOperation {
+	operation := &OpenApiOperation{
+		OperationId: record.Controller + "_" + record.Handler,
+		Summary:     record.Summary,
+		Description: record.Description,
+		Tags:        record.Tags,
+		Deprecated:  record.Deprecated,
+		Responses:   make(map[string]*OpenApiResponse),
+	}
+
+	pathParams := make(map[string]bool)
+	for _, match := range routeParamRegex.FindAllStringSubmatch(record.Path, -1) {
+		pathParams[match[1]] = true
+	}
+	if record.RequestType != nil {
+		operation.Parameters = b.buildParameters(record.RequestType, pathParams)
+		if hasRequestBody(record.Method) {
+			if body := b.schemaOf(record.RequestType); body != nil && b.hasBodyFields(record.RequestType) {
+				operation.RequestBody = &OpenApiRequestBody{
+					Required: true,
+					Content:  map[string]*OpenApiMediaType{"application/json": {Schema: body}},
+				}
+			}
+		}
+	}
+	// 未在请求结构体中声明的路径参数按字符串处理
+	var restParams []string
+	for name := range pathParams {
+		restParams = append(restParams, name)
+	}
+	sort.Strings(restParams)
+	for _, name := range restParams {
+		operation.Parameters = append(
+			operation.Parameters, &OpenApiParameter{
+				Name: name, In: "path", Required: true, Schema: &OpenApiSchema{Type: "string"},
+			},
+		)
+	}
+
+	data := &OpenApiSchema{Description: "any"}
+	if record.ResponseType != nil {
+		data = b.schemaOf(record.ResponseType)
+	}
+	description := http.StatusText(http.StatusOK)
+	if !metaresponse.UseHttpStatus {
+		description += "，错误同样以 200 返回，通过 code 区分"
+	}
+	operation.Responses["200"] = &OpenApiResponse{
+		Description: description,
+		Content:     map[string]*OpenApiMediaType{"application/json": {Schema: getResponseSchema(data)}},
+	}
+	if metaresponse.UseHttpStatus {
+		errorResponse := &OpenApiSchema{Ref: "#/components/schemas/" + openApiSchemaErrorResponse}
+		for _, status := range getErrorHttpStatuses() {
+			operation.Responses[strconv.Itoa(status)] = &OpenApiResponse{
+				Description: http.StatusText(status),
+				Content:     map[string]*OpenApiMediaType{"application/json": {Schema: errorResponse}},
+			}
+		}
+	}
+
+	if record.AuthType != nil {
+		switch *record.AuthType {
+		case AuthMiddlewareTypeRequire:
+			operation.Security = []map[string][]string{{openApiSecurityBearer: {}}}
+		case AuthMiddlewareTypeOptional:
+			operation.Security = []map[string][]string{{openApiSecurityBearer: {}}, {}}
+		}
+	}
+	return operation
+}
+
+// buildParameters 将 uri 与 form 标签的字段作为路径与查询参数，命中的路径参数从 pathParams 中移除
+func (b *openApiBuilder) buildParameters(t reflect.Type, pathParams map[string]bool) []*OpenApiParameter {
+	var parameters []*OpenApiParameter
+	forEachField(
+		t, func(field reflect.StructField) {
+			if name := getTagName(field, "uri"); name != "" {
+				delete(pathParams, name)
+				parameters = append(
+					parameters, &OpenApiParameter{
+						Name: name, In: "path", Required: true, Schema: b.schemaOf(field.Type),
+					},
+				)
+			} else if name := getTagName(field, "form"); name != "" {
+				parameters = append(
+					parameters, &OpenApiParameter{
+						Name: name, In: "query", Required: isFieldRequired(field), Schema: b.schemaOf(field.Type),
+					},
+				)
+			}
+		},
+	)
+	return parameters
+}
+
+func (b *openApiBuilder) hasBodyFields(t reflect.Type) bool {
+	has := false
+	forEachField(
+		t, func(field reflect.StructField) {
+			if isBodyField(field) {
+				has = true
+			}
+		},
+	)
+	return has
+}
+
+func (b *openApiBuilder) schemaOf(t reflect.Type) *OpenApiSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &OpenApiSchema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenApiSchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &OpenApiSchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &OpenApiSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &OpenApiSchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenApiSchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenApiSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenApiSchema{Type: "string", Format: "byte"}
+		}
+		return &OpenApiSchema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &OpenApiSchema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return b.structSchema(t)
+	default:
+		return &OpenApiSchema{}
+	}
+}
+
+// structSchema 具名结构体放入 components 中复用，匿名结构体直接展开
+func (b *openApiBuilder) structSchema(t reflect.Type) *OpenApiSchema {
+	if t.Name() == "" {
+		return b.buildStructSchema(t)
+	}
+	if name, ok := b.names[t]; ok {
+		return &OpenApiSchema{Ref: "#/components/schemas/" + name}
+	}
+	name := t.Name()
+	for i := 2; b.schemas[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", t.Name(), i)
+	}
+	b.names[t] = name
+	// 先占位，避免递归类型无限展开
+	b.schemas[name] = &OpenApiSchema{}
+	*b.schemas[name] = *b.buildStructSchema(t)
+	return &OpenApiSchema{Ref: "#/components/schemas/" + name}
+}
+
+func (b *openApiBuilder) buildStructSchema(t reflect.Type) *OpenApiSchema {
+	schema := &OpenApiSchema{
+		Type:       "object",
+		Properties: make(map[string]*OpenApiSchema),
+	}
+	forEachField(
+		t, func(field reflect.StructField) {
+			if !isBodyField(field) {
+				return
+			}
+			name := getJsonName(field)
+			schema.Properties[name] = b.schemaOf(field.Type)
+			if isFieldRequired(field) {
+				schema.Required = append(schema.Required, name)
+			}
+		},
+	)
+	return schema
+}
+
+// forEachField 遍历导出字段，展开无 json 名称的嵌入结构体
+func forEachField(t reflect.Type, callback func(field reflect.StructField)) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && getTagName(field, "json") == "" {
+			forEachField(field.Type, callback)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		callback(field)
+	}
+}
+
+// isBodyField 声明了 json 名称，或未声明 uri/form 的字段视为请求体字段
+func isBodyField(field reflect.StructField) bool {
+	if field.Tag.Get("json") == "-" {
+		return false
+	}
+	if getTagName(field, "json") != "" {
+		return true
+	}
+	return getTagName(field, "uri") == "" && getTagName(field, "form") == ""
+}
+
+func getJsonName(field reflect.StructField) string {
+	if name := getTagName(field, "json"); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+func getTagName(field reflect.StructField, tag string) string {
+	value := field.Tag.Get(tag)
+	if value == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(value, ",")
+	return name
+}
+
+func isFieldRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRequestBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodDelete, http.MethodOptions, http.MethodHead:
+		return false
+	default:
+		return true
+	}
+}
+
+func getErrorCodeSchema() *OpenApiSchema {
+	schema := &OpenApiSchema{Type: "integer"}
+	var descriptions []string
+	for _, definition := range metaerrorcode.GetDefinitions() {
+		descriptions = append(descriptions, fmt.Sprintf("%d: %s", definition.Code, definition.Name))
+	}
+	schema.Description = "响应码，" + strings.Join(descriptions, ", ")
+	return schema
+}
+
+// getResponseSchema 返回 metaresponse.Response 的结构，data 为空时不包含 data 字段
+func getResponseSchema(data *OpenApiSchema) *OpenApiSchema {
+	schema := &OpenApiSchema{
+		Type: "object",
+		Properties: map[string]*OpenApiSchema{
+			"code":       {Ref: "#/components/schemas/" + openApiSchemaErrorCode},
+			"message":    {Type: "string", Description: "错误码对应的提示文本"},
+			"request_id": {Type: "string", Description: "请求ID"},
+		},
+		Required: []string{"code"},
+	}
+	if data != nil {
+		schema.Properties["data"] = data
+	}
+	return schema
+}
+
+// getErrorHttpStatuses 返回已注册错误码用到的非 200 状态码
+func getErrorHttpStatuses() []int {
+	seen := make(map[int]bool)
+	var statuses []int
+	for _, definition := range metaerrorcode.GetDefinitions() {
+		status := definition.HttpStatus
+		if status == 0 || status == http.StatusOK || seen[status] {
+			continue
+		}
+		seen[status] = true
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	return statuses
+}
+
+// OpenApiHandler 返回 OpenAPI 文档，首次请求时生成
+func OpenApiHandler(info OpenApiInfo) gin.HandlerFunc {
+	var once sync.Once
+	var doc *OpenApi
+	return func(c *gin.Context) {
+		once.Do(
+			func() {
+				doc = GenerateOpenApi(info)
+			},
+		)
+		c.JSON(http.StatusOK, doc)
+	}
+}
+
+// SwaggerUiHandler 返回加载指定文档地址的 Swagger UI 页面
+// assetUrl 为 swagger-ui.css 与 swagger-ui-bundle.js 所在的地址，为空时使用固定版本的 unpkg 地址
+func SwaggerUiHandler(openApiPath string, assetUrl string) gin.HandlerFunc {
+	if assetUrl == "" {
+		assetUrl = defaultSwaggerUiAssetUrl
+	}
+	var page bytes.Buffer
+	err := swaggerUiTemplate.Execute(
+		&page, map[string]string{
+			"AssetUrl":    strings.TrimSuffix(assetUrl, "/"),
+			"OpenApiPath": openApiPath,
+		},
+	)
+	return func(c *gin.Context) {
+		if err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+	}
+}
+
+const defaultSwaggerUiAssetUrl = "https://unpkg.com/swagger-ui-dist@5.17.14"
+
+// swaggerUiTemplate 由 html/template 按上下文转义，文档地址在脚本中输出为 JS 字符串
+var swaggerUiTemplate = template.Must(
+	template.New("swagger-ui").Parse(
+		`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>API</title>
+<link rel="stylesheet" href="{{.AssetUrl}}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="{{.AssetUrl}}/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({url: {{.OpenApiPath}}, dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`,
+	),
+)
@@ -0,0 +1,102 @@
+package metahttp
+
+import (
+	metaresponse "meta/meta-response"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *testUserController) DescribeRoutes() map[string]*Route {
+	return map[string]*Route{
+		"DeleteUser": {
+			Path:       "/user/:id",
+			Deprecated: true,
+			Summary:    "Delete user",
+		},
+	}
+}
+
+func TestGenerateOpenApi(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	AutoRegisterRoute(r.Group("/api"), "admin", &testUserController{}, AuthMiddlewareTypeNone)
+
+	doc := GenerateOpenApi(OpenApiInfo{Title: "test", Version: "1.0.0"})
+
+	post := doc.Paths["/api/admin/user"]["post"]
+	if post == nil {
+		t.Fatalf("Expected POST /api/admin/user in paths, got %v", doc.Paths)
+	}
+	if post.RequestBody == nil {
+		t.Fatalf("Expected request body for POST")
+	}
+	var query *OpenApiParameter
+	for _, parameter := range post.Parameters {
+		if parameter.In == "query" && parameter.Name == "page" {
+			query = parameter
+		}
+	}
+	if query == nil {
+		t.Errorf("Expected query parameter page, got %+v", post.Parameters)
+	}
+
+	body := doc.Components.Schemas["testUserReq"]
+	if body == nil {
+		t.Fatalf("Expected testUserReq schema")
+	}
+	if _, ok := body.Properties["page"]; ok {
+		t.Errorf("Expected query field excluded from body schema")
+	}
+	if len(body.Required) != 1 || body.Required[0] != "name" {
+		t.Errorf("Expected required [name], got %v", body.Required)
+	}
+
+	deleteOperation := doc.Paths["/api/admin/user/{id}"]["delete"]
+	if deleteOperation == nil || !deleteOperation.Deprecated || deleteOperation.Summary != "Delete user" {
+		t.Fatalf("Expected deprecated DELETE /api/admin/user/{id}, got %+v", deleteOperation)
+	}
+	if deleteOperation.Parameters[0].In != "path" || deleteOperation.Parameters[0].Name != "id" {
+		t.Errorf("Expected path parameter id, got %+v", deleteOperation.Parameters[0])
+	}
+
+	envelope := post.Responses["200"].Content["application/json"].Schema
+	for _, name := range []string{"code", "message", "request_id", "data"} {
+		if _, ok := envelope.Properties[name]; !ok {
+			t.Errorf("Expected envelope property %s, got %v", name, envelope.Properties)
+		}
+	}
+	if len(post.Responses) != 1 {
+		t.Errorf("Expected only 200 response by default, got %v", post.Responses)
+	}
+
+	metaresponse.UseHttpStatus = true
+	defer func() {
+		metaresponse.UseHttpStatus = false
+	}()
+	doc = GenerateOpenApi(OpenApiInfo{Title: "test", Version: "1.0.0"})
+	post = doc.Paths["/api/admin/user"]["post"]
+	for _, status := range []string{"400", "429", "500"} {
+		if post.Responses[status] == nil {
+			t.Errorf("Expected %s response with UseHttpStatus, got %v", status, post.Responses)
+		}
+	}
+}
+
+func TestSwaggerUiHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/docs", SwaggerUiHandler(`/openapi.json?a=1"</script><script>alert(1)</script>`, ""))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	page := w.Body.String()
+	if !strings.Contains(page, defaultSwaggerUiAssetUrl+"/swagger-ui-bundle.js") {
+		t.Errorf("page should load pinned assets: %s", page)
+	}
+	if strings.Contains(page, "<script>alert(1)") || !strings.Contains(page, `url: "/openapi.json?a=1\"\u003c/script\u003e`) {
+		t.Errorf("openapi path should be escaped as a js string: %s", page)
+	}
+}
@@ -0,0 +1,90 @@
+package metahttp
+
+import (
+	"log/slog"
+	metaerrorcode "meta/error-code"
+	metaresponse "meta/meta-response"
+	"meta/ratelimit"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKeyFunc 返回限流的 key，返回空字符串时不限流
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByIp 按客户端 IP 限流，未配置 TrustedProxies 时信任所有代理，X-Forwarded-For 可被伪造
+func RateLimitByIp(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitBySubject 按鉴权主体限流，未鉴权时按 IP 限流
+func RateLimitBySubject(c *gin.Context) string {
+	if subject := GetJwtSubject(c); subject != "" {
+		return "sub:" + subject
+	}
+	return RateLimitByIp(c)
+}
+
+// RateLimitByRoute 按路由限流，所有调用方共享额度
+func RateLimitByRoute(c *gin.Context) string {
+	return "route:" + c.Request.Method + ":" + c.FullPath()
+}
+
+type RateLimitConfig struct {
+	Name     string            // 规则名称，作为 key 前缀区分不同规则
+	Limiter  ratelimit.Limiter // ratelimit.NewMemoryLimiter 或 ratelimit.NewRedisLimiter
+	KeyFunc  RateLimitKeyFunc  // 默认为 RateLimitByIp
+	FailOpen bool              // 限流器出错时记录日志并放行，否则返回 ServiceUnavailable
+}
+
+// RateLimitMiddleware 超出限制时返回 TooManyRequests，并设置 RateLimit-* 与 Retry-After 头
+// 限流器出错时按 FailOpen 放行或返回 ServiceUnavailable
+func RateLimitMiddleware(config *RateLimitConfig) gin.HandlerFunc {
+	if config.KeyFunc == nil {
+		config.KeyFunc = RateLimitByIp
+	}
+	return func(c *gin.Context) {
+		key := config.KeyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if config.Name != "" {
+			key = config.Name + ":" + key
+		}
+		result, err := config.Limiter.Allow(c, key)
+		if err != nil {
+			// 限流器不可用不是调用方的问题，不返回 TooManyRequests
+			slog.WarnContext(c, "rate limiter failed", "key", key, "fail_open", config.FailOpen, "err", err)
+			if config.FailOpen {
+				c.Next()
+				return
+			}
+			_ = c.Error(err)
+			metaresponse.NewResponse(c, metaerrorcode.ErrorCode(http.StatusServiceUnavailable))
+			c.Abort()
+			return
+		}
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			slog.InfoContext(c, "rate limited", "key", key, "path", c.Request.URL.Path)
+			metaresponse.NewResponse(c, metaerrorcode.TooManyRequests)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,81 @@
+package metahttp
+
+import (
+	"context"
+	"errors"
+	"meta/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type errorLimiter struct {
+}
+
+func (l *errorLimiter) Allow(context.Context, string) (*ratelimit.Result, error) {
+	return nil, errors.New("redis down")
+}
+
+func newRateLimitEngine(t *testing.T, trustedProxies []string, config *RateLimitConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		t.Fatal(err)
+	}
+	r.Use(RateLimitMiddleware(config))
+	r.GET(
+		"/", func(c *gin.Context) {
+			c.String(http.StatusOK, "ok")
+		},
+	)
+	return r
+}
+
+func doRateLimitRequest(r *gin.Engine, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	rule := ratelimit.Rule{Limit: 1, Window: time.Minute}
+
+	// 不信任任何代理时伪造 X-Forwarded-For 无效
+	r := newRateLimitEngine(t, nil, &RateLimitConfig{Limiter: ratelimit.NewMemoryLimiter(rule)})
+	if w := doRateLimitRequest(r, "10.0.0.1"); w.Body.String() != "ok" || w.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("first request should pass: %s %v", w.Body.String(), w.Header())
+	}
+	w := doRateLimitRequest(r, "10.0.0.2")
+	if w.Body.String() == "ok" || w.Header().Get("Retry-After") != "60" {
+		t.Errorf("spoofed request should be limited: %s %v", w.Body.String(), w.Header())
+	}
+
+	// 来自可信代理时按转发的客户端 IP 限流
+	r = newRateLimitEngine(t, []string{"192.0.2.1"}, &RateLimitConfig{Limiter: ratelimit.NewMemoryLimiter(rule)})
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		if w := doRateLimitRequest(r, ip); w.Body.String() != "ok" {
+			t.Errorf("request from %s should pass", ip)
+		}
+	}
+	if w := doRateLimitRequest(r, "10.0.0.1"); w.Body.String() == "ok" {
+		t.Error("second request from same client should be limited")
+	}
+
+	r = newRateLimitEngine(t, nil, &RateLimitConfig{Limiter: &errorLimiter{}, FailOpen: true})
+	if w := doRateLimitRequest(r, ""); w.Body.String() != "ok" {
+		t.Error("fail open should pass when limiter fails")
+	}
+	r = newRateLimitEngine(t, nil, &RateLimitConfig{Limiter: &errorLimiter{}})
+	if w := doRateLimitRequest(r, ""); w.Body.String() == "ok" || !strings.Contains(w.Body.String(), "503") {
+		t.Errorf("fail closed should reject with service unavailable: %s", w.Body.String())
+	}
+}
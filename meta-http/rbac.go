@@ -0,0 +1,188 @@
+package metahttp
+
+import (
+	"log/slog"
+	metaresponse "meta/meta-response"
+	"meta/rbac"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ContextKeyRbacGrants = "meta-rbac-grants"
+)
+
+type RbacConfig struct {
+	Authorizer *rbac.Authorizer
+	GetSubject func(c *gin.Context) string // 默认为 GetJwtSubject
+}
+
+var rbacConfig *RbacConfig
+
+type groupPermission struct {
+	basePath    string
+	roles       []string
+	permissions []string
+}
+
+var (
+	groupPermissionMutex sync.RWMutex
+	groupPermissions     []*groupPermission
+)
+
+// UseRbac 启用权限校验，需在注册路由前调用
+func UseRbac(config *RbacConfig) {
+	if config.GetSubject == nil {
+		config.GetSubject = GetJwtSubject
+	}
+	rbacConfig = config
+}
+
+// RequirePermission 需要拥有全部权限，权限中的 {name} 会替换为路径参数
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return newRbacHandler(nil, permissions)
+}
+
+// RequireRole 需要拥有任一角色
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return newRbacHandler(roles, nil)
+}
+
+// UseGroupPermission 为路由组添加权限校验，并记录到路由列表中
+// 与 gin 的 Use 相同，只作用于之后注册的路由
+func UseGroupPermission(group *gin.RouterGroup, roles []string, permissions ...string) {
+	groupPermissionMutex.Lock()
+	groupPermissions = append(
+		groupPermissions, &groupPermission{
+			basePath:    group.BasePath(),
+			roles:       roles,
+			permissions: permissions,
+		},
+	)
+	groupPermissionMutex.Unlock()
+	group.Use(newRbacHandler(roles, permissions))
+}
+
+// getGroupPermission 返回路径所属路由组要求的角色与权限
+func getGroupPermission(path string) ([]string, []string) {
+	groupPermissionMutex.RLock()
+	defer groupPermissionMutex.RUnlock()
+	var roles []string
+	var permissions []string
+	for _, group := range groupPermissions {
+		basePath := strings.TrimSuffix(group.basePath, "/")
+		if path != basePath && !strings.HasPrefix(path, basePath+"/") {
+			continue
+		}
+		roles = append(roles, group.roles...)
+		permissions = append(permissions, group.permissions...)
+	}
+	return roles, permissions
+}
+
+func newRbacHandler(roles []string, permissions []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rbacConfig == nil || rbacConfig.Authorizer == nil {
+			slog.ErrorContext(c, "rbac not enabled", "path", c.Request.URL.Path)
+			abortForbidden(c)
+			return
+		}
+		grants := GetRbacGrants(c)
+		if grants == nil {
+			subject := rbacConfig.GetSubject(c)
+			if subject == "" {
+				abortUnauthorized(c)
+				return
+			}
+			var err error
+			grants, err = rbacConfig.Authorizer.GetGrants(c, subject)
+			if err != nil {
+				_ = c.Error(err)
+				abortForbidden(c)
+				return
+			}
+			c.Set(ContextKeyRbacGrants, grants)
+		}
+		if !grants.HasAnyRole(roles...) {
+			slog.InfoContext(c, "rbac role denied", "subject", grants.Subject, "roles", roles)
+			abortForbidden(c)
+			return
+		}
+		for _, permission := range permissions {
+			permission = rbac.ExpandPermission(permission, c.Param)
+			if !grants.HasPermission(permission) {
+				slog.InfoContext(c, "rbac permission denied", "subject", grants.Subject, "permission", permission)
+				abortForbidden(c)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+func abortForbidden(c *gin.Context) {
+	metaresponse.NewResponse(c, http.StatusForbidden)
+	c.Abort()
+}
+
+// GetRbacGrants 返回权限中间件读取的角色与权限，未经过权限中间件时返回 nil
+func GetRbacGrants(c *gin.Context) *rbac.Grants {
+	value, ok := c.Get(ContextKeyRbacGrants)
+	if !ok {
+		return nil
+	}
+	grants, _ := value.(*rbac.Grants)
+	return grants
+}
+
+type RoutePermission struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Controller  string   `json:"controller"`
+	Handler     string   `json:"handler"`
+	Auth        string   `json:"auth"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// GetRoutePermissions 返回所有自动注册路由要求的鉴权、角色与权限
+func GetRoutePermissions() []*RoutePermission {
+	records := GetRouteRecords()
+	result := make([]*RoutePermission, 0, len(records))
+	for _, record := range records {
+		result = append(
+			result, &RoutePermission{
+				Method:      record.Method,
+				Path:        record.Path,
+				Controller:  record.Controller,
+				Handler:     record.Handler,
+				Auth:        getAuthTypeName(record.AuthType),
+				Roles:       record.Roles,
+				Permissions: record.Permissions,
+			},
+		)
+	}
+	return result
+}
+
+// RoutePermissionHandler 供管理后台查看路由权限
+func RoutePermissionHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, GetRoutePermissions())
+}
+
+func getAuthTypeName(authMiddlewareType *AuthMiddlewareType) string {
+	if authMiddlewareType == nil {
+		return "custom"
+	}
+	switch *authMiddlewareType {
+	case AuthMiddlewareTypeNone:
+		return "none"
+	case AuthMiddlewareTypeOptional:
+		return "optional"
+	default:
+		return "require"
+	}
+}
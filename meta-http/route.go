@@ -1,84 +1,200 @@
 package metahttp
 
 import (
-	"github.com/gin-gonic/gin"
 	"log/slog"
 	metacontroller "meta/controller"
 	"reflect"
 	"strings"
+
+	"github.com/gin-gonic/gin"
 )
 
 var AuthMiddleware gin.HandlerFunc
 var AuthMiddlewareOptional gin.HandlerFunc
 
+// 根据方法名前缀推断 HTTP 方法，按顺序匹配
+var routeMethodPrefixes = []struct {
+	prefix string
+	method string
+}{
+	{"Get", "GET"},
+	{"Post", "POST"},
+	{"Put", "PUT"},
+	{"Patch", "PATCH"},
+	{"Delete", "DELETE"},
+	{"Options", "OPTIONS"},
+}
+
+// Route 单个控制器方法的路由描述，未设置的字段使用方法名推断或控制器级别的配置
+type Route struct {
+	Path        string              // 相对于 basePath 的路径，支持 :param 与 *param
+	Method      string              // HTTP 方法
+	AuthType    *AuthMiddlewareType // 鉴权类型，覆盖控制器级别的鉴权类型
+	Middlewares []gin.HandlerFunc   // 仅作用于该路由的中间件，在鉴权之后执行
+	Deprecated  bool                // 已废弃，响应中附带 Deprecation 头
+	Summary     string              // 接口文档摘要
+	Description string              // 接口文档描述
+	Tags        []string            // 接口文档分组，默认为控制器名称
+	Roles       []string            // 需要拥有任一角色，需先调用 UseRbac
+	Permissions []string            // 需要拥有全部权限，支持 {param} 引用路径参数
+}
+
+// RouteDescriber 控制器可选实现，按方法名返回路由描述
+type RouteDescriber interface {
+	DescribeRoutes() map[string]*Route
+}
+
+// PathParamRouter 控制器可选实现，实现后方法名中 By 之后的单词作为路径参数
+// 如 GetUserByIdPosts 注册为 GET {basePath}/user/:id/posts，未实现时为 /user/by/id/posts
+type PathParamRouter interface {
+	UsePathParams()
+}
+
+func AuthType(authMiddlewareType AuthMiddlewareType) *AuthMiddlewareType {
+	return &authMiddlewareType
+}
+
 func AutoRegisterRoute(r gin.IRoutes, basePath string, controller metacontroller.Interface, authMiddlewareType AuthMiddlewareType) {
-	switch authMiddlewareType {
-	case AuthMiddlewareTypeNone:
-		AutoRegisterRouteWithHandle(r, basePath, controller)
-	case AuthMiddlewareTypeOptional:
-		if AuthMiddlewareOptional == nil {
-			AutoRegisterRouteWithHandle(r, basePath, controller, AuthMiddleware)
-		} else {
-			AutoRegisterRouteWithHandle(r, basePath, controller, AuthMiddlewareOptional)
-		}
-	default:
-		AutoRegisterRouteWithHandle(r, basePath, controller, AuthMiddleware)
-	}
+	autoRegisterRoute(r, basePath, controller, &authMiddlewareType, nil)
 }
 
 func AutoRegisterRouteWithHandle(r gin.IRoutes, basePath string, controller metacontroller.Interface, handlers ...gin.HandlerFunc) {
+	autoRegisterRoute(r, basePath, controller, nil, handlers)
+}
+
+// autoRegisterRoute 按方法名顺序注册控制器的所有路由
+// 方法名规则：{HTTP方法}{路径}，路径按驼峰拆分
+// 如 GetUserList 注册为 GET {basePath}/user/list
+func autoRegisterRoute(
+	r gin.IRoutes,
+	basePath string,
+	controller metacontroller.Interface,
+	authMiddlewareType *AuthMiddlewareType,
+	handlers []gin.HandlerFunc,
+) {
 	ctrlVal := reflect.ValueOf(controller)
 	ctrlType := reflect.TypeOf(controller)
 
+	var routes map[string]*Route
+	if describer, ok := controller.(RouteDescriber); ok {
+		routes = describer.DescribeRoutes()
+	}
+	_, pathParams := controller.(PathParamRouter)
+
 	for i := 0; i < ctrlVal.NumMethod(); i++ {
 		method := ctrlVal.Method(i)
 		methodName := ctrlType.Method(i).Name
 
-		// 根据方法名自动推断 HTTP方法和路径
-		var maps = map[string]string{
-			"Get":     "GET",
-			"Post":    "POST",
-			"Put":     "PUT",
-			"Delete":  "DELETE",
-			"Options": "OPTIONS",
+		route := routes[methodName]
+		httpMethod, path, ok := parseMethodName(methodName, pathParams)
+		if !ok && (route == nil || route.Method == "") {
+			continue
 		}
-		for k, v := range maps {
-			if strings.HasPrefix(methodName, k) {
-				httpMethod := v
-				path := strings.TrimPrefix(methodName, k)
-
-				path = strings.Join(splitCamelCase(path), "/")
-
-				realPath := basePath
-				if path != "" {
-					realPath = realPath + "/" + path
-				}
-
-				callHandler := func(c *gin.Context) {
-					method.Call([]reflect.Value{reflect.ValueOf(c)})
-				}
-
-				if len(handlers) > 0 {
-					realHandlers := append(handlers, callHandler)
-					r.Handle(httpMethod, realPath, realHandlers...)
-				} else {
-					r.Handle(httpMethod, realPath, callHandler)
-				}
-
-				slog.Info(
-					"auto register path",
-					"method",
-					httpMethod,
-					"path",
-					realPath,
-					"extraHandler",
-					len(handlers),
-				)
+		if route != nil {
+			if route.Method != "" {
+				httpMethod = strings.ToUpper(route.Method)
+			}
+			if route.Path != "" {
+				path = strings.TrimPrefix(route.Path, "/")
 			}
 		}
+
+		realPath := basePath
+		if path != "" {
+			realPath = realPath + "/" + path
+		}
+
+		callHandler := createHandler(method)
+		if callHandler == nil {
+			slog.Warn("auto register skip unsupported method", "method", methodName, "type", method.Type().String())
+			continue
+		}
+
+		realHandlers := getRouteHandlers(authMiddlewareType, handlers, route)
+		realHandlers = append(realHandlers, callHandler)
+		r.Handle(httpMethod, realPath, realHandlers...)
+
+		addRouteRecord(newRouteRecord(r, httpMethod, realPath, ctrlType, methodName, method.Type(), authMiddlewareType, route))
+
+		slog.Info(
+			"auto register path",
+			"method",
+			httpMethod,
+			"path",
+			realPath,
+			"extraHandler",
+			len(realHandlers)-1,
+			"deprecated",
+			route != nil && route.Deprecated,
+		)
 	}
 }
 
+// parseMethodName 由方法名推断 HTTP 方法与路径，pathParams 为 true 时 By 之后的单词作为路径参数
+func parseMethodName(methodName string, pathParams bool) (string, string, bool) {
+	for _, prefix := range routeMethodPrefixes {
+		if !strings.HasPrefix(methodName, prefix.prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(methodName, prefix.prefix)
+		if name == "" {
+			return prefix.method, "", true
+		}
+		words := splitCamelCase(name)
+		var segments []string
+		for j := 0; j < len(words); j++ {
+			if pathParams && words[j] == "by" && j+1 < len(words) {
+				segments = append(segments, ":"+words[j+1])
+				j++
+				continue
+			}
+			segments = append(segments, words[j])
+		}
+		return prefix.method, strings.Join(segments, "/"), true
+	}
+	return "", "", false
+}
+
+func getRouteHandlers(authMiddlewareType *AuthMiddlewareType, handlers []gin.HandlerFunc, route *Route) []gin.HandlerFunc {
+	var realHandlers []gin.HandlerFunc
+	if route != nil && route.AuthType != nil {
+		authMiddlewareType = route.AuthType
+	}
+	if authMiddlewareType != nil {
+		realHandlers = append(realHandlers, getAuthHandlers(*authMiddlewareType)...)
+	}
+	realHandlers = append(realHandlers, handlers...)
+	if route != nil {
+		if route.Deprecated {
+			realHandlers = append(realHandlers, deprecatedMiddleware)
+		}
+		if len(route.Roles) > 0 || len(route.Permissions) > 0 {
+			realHandlers = append(realHandlers, newRbacHandler(route.Roles, route.Permissions))
+		}
+		realHandlers = append(realHandlers, route.Middlewares...)
+	}
+	return realHandlers
+}
+
+func getAuthHandlers(authMiddlewareType AuthMiddlewareType) []gin.HandlerFunc {
+	switch authMiddlewareType {
+	case AuthMiddlewareTypeNone:
+		return nil
+	case AuthMiddlewareTypeOptional:
+		if AuthMiddlewareOptional == nil {
+			return []gin.HandlerFunc{AuthMiddleware}
+		}
+		return []gin.HandlerFunc{AuthMiddlewareOptional}
+	default:
+		return []gin.HandlerFunc{AuthMiddleware}
+	}
+}
+
+func deprecatedMiddleware(c *gin.Context) {
+	c.Header("Deprecation", "true")
+	c.Next()
+}
+
 func splitCamelCase(s string) []string {
 	var words []string
 	var currentWord strings.Builder
@@ -92,3 +208,56 @@ func splitCamelCase(s string) []string {
 	words = append(words, strings.ToLower(currentWord.String()))
 	return words
 }
+
+func newRouteRecord(
+	r gin.IRoutes,
+	httpMethod string,
+	path string,
+	ctrlType reflect.Type,
+	methodName string,
+	methodType reflect.Type,
+	authMiddlewareType *AuthMiddlewareType,
+	route *Route,
+) *RouteRecord {
+	if group, ok := r.(interface{ BasePath() string }); ok {
+		path = joinRoutePath(group.BasePath(), path)
+	}
+	controllerName := ctrlType.String()
+	if ctrlType.Kind() == reflect.Ptr {
+		controllerName = ctrlType.Elem().Name()
+	}
+	record := &RouteRecord{
+		Method:     httpMethod,
+		Path:       path,
+		Controller: controllerName,
+		Handler:    methodName,
+		AuthType:   authMiddlewareType,
+	}
+	record.RequestType, record.ResponseType = getHandlerTypes(methodType)
+	record.Roles, record.Permissions = getGroupPermission(path)
+	if route != nil {
+		if route.AuthType != nil {
+			record.AuthType = route.AuthType
+		}
+		record.Deprecated = route.Deprecated
+		record.Summary = route.Summary
+		record.Description = route.Description
+		record.Tags = route.Tags
+		record.Roles = append(record.Roles, route.Roles...)
+		record.Permissions = append(record.Permissions, route.Permissions...)
+	}
+	if len(record.Tags) == 0 {
+		record.Tags = []string{controllerName}
+	}
+	return record
+}
+
+func joinRoutePath(basePath string, path string) string {
+	if basePath == "" || basePath == "/" {
+		if !strings.HasPrefix(path, "/") {
+			return "/" + path
+		}
+		return path
+	}
+	return strings.TrimSuffix(basePath, "/") + "/" + strings.TrimPrefix(path, "/")
+}
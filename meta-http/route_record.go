@@ -0,0 +1,52 @@
+package metahttp
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// RouteRecord 自动注册的路由记录
+type RouteRecord struct {
+	Method       string
+	Path         string // 完整路径，包含路由组前缀
+	Controller   string
+	Handler      string
+	AuthType     *AuthMiddlewareType // 为空表示由调用方自行传入中间件
+	Deprecated   bool
+	Summary      string
+	Description  string
+	Tags         []string
+	Roles        []string     // 包含路由组要求的角色
+	Permissions  []string     // 包含路由组要求的权限
+	RequestType  reflect.Type // 请求结构体类型，为空表示未使用类型化请求
+	ResponseType reflect.Type // 响应数据类型，为空表示未使用类型化响应
+}
+
+var (
+	routeRecordMutex sync.RWMutex
+	routeRecords     []*RouteRecord
+)
+
+func addRouteRecord(record *RouteRecord) {
+	routeRecordMutex.Lock()
+	defer routeRecordMutex.Unlock()
+	routeRecords = append(routeRecords, record)
+}
+
+// GetRouteRecords 返回所有已注册的路由，按路径与方法排序
+func GetRouteRecords() []*RouteRecord {
+	routeRecordMutex.RLock()
+	defer routeRecordMutex.RUnlock()
+	result := make([]*RouteRecord, len(routeRecords))
+	copy(result, routeRecords)
+	sort.SliceStable(
+		result, func(i, j int) bool {
+			if result[i].Path != result[j].Path {
+				return result[i].Path < result[j].Path
+			}
+			return result[i].Method < result[j].Method
+		},
+	)
+	return result
+}
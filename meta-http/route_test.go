@@ -0,0 +1,30 @@
+package metahttp
+
+import "testing"
+
+func TestParseMethodName(t *testing.T) {
+	tests := []struct {
+		methodName string
+		pathParams bool
+		method     string
+		path       string
+		ok         bool
+	}{
+		{methodName: "Get", method: "GET", path: "", ok: true},
+		{methodName: "GetUserList", method: "GET", path: "user/list", ok: true},
+		{methodName: "GetUserByName", method: "GET", path: "user/by/name", ok: true},
+		{methodName: "PatchUserById", pathParams: true, method: "PATCH", path: "user/:id", ok: true},
+		{methodName: "DeleteUserByIdPostByPost", pathParams: true, method: "DELETE", path: "user/:id/post/:post", ok: true},
+		{methodName: "GetSortBy", pathParams: true, method: "GET", path: "sort/by", ok: true},
+		{methodName: "DescribeRoutes", ok: false},
+	}
+	for _, tt := range tests {
+		method, path, ok := parseMethodName(tt.methodName, tt.pathParams)
+		if ok != tt.ok || method != tt.method || path != tt.path {
+			t.Errorf(
+				"parseMethodName(%s, %v) = %s %s %v, want %s %s %v",
+				tt.methodName, tt.pathParams, method, path, ok, tt.method, tt.path, tt.ok,
+			)
+		}
+	}
+}
@@ -1,15 +1,21 @@
 package metahttp
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	metaerrorcode "meta/error-code"
+	metaerror "meta/meta-error"
 	metaflag "meta/meta-flag"
 	metalog "meta/meta-log"
+	metametrics "meta/meta-metrics"
 	metapanic "meta/meta-panic"
+	metaredact "meta/meta-redact"
 	metaresponse "meta/meta-response"
 	"meta/metaroutine"
 	"meta/subsystem"
+	"net"
 	"net/http"
 	"time"
 
@@ -20,19 +26,56 @@ import (
 type Subsystem struct {
 	subsystem.Subsystem
 	GetPort    func() int32
+	GetConfig  func() *Config
 	ProcessGin func(r *gin.Engine)
+
+	server          *http.Server
+	shutdownTimeout time.Duration
 }
 
 func (s *Subsystem) GetName() string {
 	return "Http"
 }
 
+// Start 同步监听端口，监听失败时返回错误，之后在后台处理请求
 func (s *Subsystem) Start() error {
-	metaroutine.SafeGoWithRestart(
-		"Http start",
+	config := &Config{}
+	if s.GetConfig != nil {
+		if c := s.GetConfig(); c != nil {
+			// 复制一份再填充默认值，不修改调用方的配置
+			copied := *c
+			config = &copied
+		}
+	}
+	config.setDefault()
+
+	handler, err := s.createEngine(config)
+	if err != nil {
+		return err
+	}
+
+	port := s.GetPort()
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return metaerror.Wrap(err, "http listen failed, port:%d", port)
+	}
+
+	s.shutdownTimeout = config.ShutdownTimeout
+	s.server = &http.Server{
+		Handler:           handler,
+		ReadTimeout:       config.ReadTimeout,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+	}
+	slog.Info("Http server listen start", "port", port)
+
+	server := s.server
+	metaroutine.SafeGo(
+		"Http serve",
 		func() error {
-			err := s.startSubsystem()
-			if err != nil {
+			err := server.Serve(listener)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
 				return err
 			}
 			return nil
@@ -41,19 +84,43 @@ func (s *Subsystem) Start() error {
 	return nil
 }
 
-func (s *Subsystem) startSubsystem() error {
-
-	port := s.GetPort()
+// Stop 停止接收新请求，并等待处理中的请求完成
+func (s *Subsystem) Stop() error {
+	if s.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	slog.Info("Http server shutdown begin")
+	err := s.server.Shutdown(ctx)
+	if err != nil {
+		return metaerror.Wrap(err, "http server shutdown failed")
+	}
+	slog.Info("Http server shutdown end")
+	return nil
+}
 
+func (s *Subsystem) createEngine(config *Config) (*gin.Engine, error) {
 	if !metaflag.IsDebug() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	// 创建默认路由引擎
 	r := gin.New()
+	// 使 gin.Context 作为 context 使用时能读取到请求 context 中的值
+	r.ContextWithFallback = true
+	// gin 默认信任所有代理，客户端可伪造 X-Forwarded-For，配置后只信任指定的代理
+	if len(config.TrustedProxies) > 0 {
+		if err := r.SetTrustedProxies(config.TrustedProxies); err != nil {
+			return nil, metaerror.Wrap(err, "set trusted proxies failed")
+		}
+	}
 
 	// 手动添加中间件
-	r.Use(GinLogger(metalog.GetLogger()))
+	r.Use(TraceMiddleware())
+	r.Use(TracingMiddleware())
+	r.Use(MetricsMiddleware())
+	r.Use(GinLogger(metalog.GetModuleLogger(metalog.LoggerGin)))
 	r.Use(gzip.Gzip(gzip.DefaultCompression))
 	// 需要先设置CORS，否则错误可能会被拦截
 	r.Use(CORSMiddleware())
@@ -66,15 +133,25 @@ func (s *Subsystem) startSubsystem() error {
 
 	s.ProcessGin(r)
 
-	slog.Info("Http server listen start", "port", port)
+	if config.MetricsPath != "" {
+		r.GET(config.MetricsPath, gin.WrapH(metametrics.Handler()))
+	}
 
-	// 启动
-	err := r.Run(fmt.Sprintf(":%d", port))
-	if err != nil {
-		return err
+	if config.OpenApiPath != "" {
+		r.GET(
+			config.OpenApiPath, OpenApiHandler(
+				OpenApiInfo{
+					Title:   config.OpenApiTitle,
+					Version: config.OpenApiVersion,
+				},
+			),
+		)
+		if config.SwaggerUiPath != "" {
+			r.GET(config.SwaggerUiPath, SwaggerUiHandler(config.OpenApiPath, config.SwaggerUiAssetUrl))
+		}
 	}
 
-	return nil
+	return r, nil
 }
 
 func GinLogger(logger *slog.Logger) gin.HandlerFunc {
@@ -89,7 +166,8 @@ func GinLogger(logger *slog.Logger) gin.HandlerFunc {
 		duration := time.Since(start)
 
 		// 记录日志信息
-		logger.Info(
+		logger.InfoContext(
+			ctx,
 			"Gin",
 			slog.String("method", ctx.Request.Method),
 			slog.String("path", ctx.Request.URL.Path),
@@ -105,10 +183,7 @@ func ErrorHandlingMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				response := metaresponse.Response{
-					Code: int(metaerrorcode.PanicError),
-				}
-				ctx.JSON(http.StatusOK, response)
+				metaresponse.NewResponse(ctx, metaerrorcode.PanicError)
 				metapanic.ProcessPanic(
 					"gin panic", err,
 					"ErrorHandlingMiddleware panic\nip: %s\nhost: %s\npath: %s\nmethod: %s\nparam: %v",
@@ -116,11 +191,21 @@ func ErrorHandlingMiddleware() gin.HandlerFunc {
 					ctx.Request.Host,
 					ctx.Request.URL.Path,
 					ctx.Request.Method,
-					ctx.Request.URL.Query(),
+					metaredact.Query(ctx.Request.URL.Query()).Encode(),
 				)
 				ctx.Abort()
 			}
 			for _, err := range ctx.Errors {
+				// 低于 Error 级别的错误码只记录日志，不触发告警
+				if level := metaerrorcode.GetLevel(metaerror.GetErrorCodeFromError(err.Err)); level < slog.LevelError {
+					slog.Log(
+						ctx, level, "request error",
+						"path", ctx.Request.URL.Path,
+						"err", err.Err,
+						"fields", metaerror.GetFields(err.Err),
+					)
+					continue
+				}
 				metapanic.ProcessError(
 					err.Err,
 					"ErrorHandlingMiddleware error\nip: %s\nhost: %s\npath: %s\nmethod: %s\nparam: %v",
@@ -128,7 +213,7 @@ func ErrorHandlingMiddleware() gin.HandlerFunc {
 					ctx.Request.Host,
 					ctx.Request.URL.Path,
 					ctx.Request.Method,
-					ctx.Request.URL.Query().Encode(),
+					metaredact.Query(ctx.Request.URL.Query()).Encode(),
 				)
 			}
 
@@ -0,0 +1,45 @@
+package metahttp
+
+import (
+	"context"
+	metatrace "meta/meta-trace"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TraceMiddleware 读取或生成请求ID与 traceparent 并写入请求 context
+// 入站 traceparent 的 trace id 保持不变，span id 替换为本服务生成的值
+// 原始的 traceparent 另行保存，TracingMiddleware 开启的 span 以调用方为父节点
+func TraceMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		requestId := c.GetHeader(metatrace.HeaderRequestId)
+		if !metatrace.IsValidRequestId(requestId) {
+			requestId = metatrace.NewRequestId()
+		}
+		traceParent, ok := metatrace.ParseTraceParent(c.GetHeader(metatrace.HeaderTraceParent))
+		if ok {
+			ctx = metatrace.WithRemoteTraceParent(ctx, traceParent.String())
+			traceParent = traceParent.Child()
+		} else {
+			traceParent = metatrace.NewTraceParent()
+		}
+		ctx = metatrace.WithRequestId(ctx, requestId)
+		ctx = metatrace.WithTraceParent(ctx, traceParent.String())
+		c.Request = c.Request.WithContext(ctx)
+		c.Header(metatrace.HeaderRequestId, requestId)
+		c.Next()
+	}
+}
+
+// InjectTraceHeader 将 context 中的请求ID与 traceparent 写入出站请求头
+func InjectTraceHeader(ctx context.Context, header http.Header) {
+	metatrace.Inject(
+		ctx, func(key string, value string) {
+			if header.Get(key) == "" {
+				header.Set(key, value)
+			}
+		},
+	)
+}
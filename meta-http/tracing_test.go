@@ -0,0 +1,56 @@
+package metahttp
+
+import (
+	metatrace "meta/meta-trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingMiddlewareKeepsTraceParent(t *testing.T) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var before, after metatrace.TraceParent
+	r.Use(TraceMiddleware())
+	r.Use(
+		func(c *gin.Context) {
+			before, _ = metatrace.ParseTraceParent(metatrace.GetTraceParent(c.Request.Context()))
+		},
+	)
+	r.Use(TracingMiddleware())
+	var parent trace.SpanContext
+	r.GET(
+		"/", func(c *gin.Context) {
+			after, _ = metatrace.ParseTraceParent(metatrace.GetTraceParent(c.Request.Context()))
+			if span, ok := trace.SpanFromContext(c.Request.Context()).(sdktrace.ReadOnlySpan); ok {
+				parent = span.Parent()
+			}
+		},
+	)
+
+	inbound := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	for _, header := range []string{"", "invalid", inbound} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set(metatrace.HeaderTraceParent, header)
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		if before.TraceId == "" || after.TraceId != before.TraceId {
+			t.Errorf("header %q: trace id changed from %q to %q", header, before.TraceId, after.TraceId)
+		}
+		if after.ParentId == before.ParentId {
+			t.Errorf("header %q: span should replace parent id", header)
+		}
+		if header == inbound && parent.SpanID().String() != "00f067aa0ba902b7" {
+			t.Errorf("server span parent should be the inbound span: %s", parent.SpanID())
+		}
+	}
+}
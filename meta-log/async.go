@@ -0,0 +1,131 @@
+package metalog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+type asyncEntry struct {
+	data []byte
+	done chan struct{} // 非空时为 Flush 标记
+}
+
+// AsyncWriter 将写入放入缓冲队列，由后台协程写入下层 writer，调用方不再等待磁盘或网络
+// 队列满时默认阻塞，设置 dropWhenFull 后丢弃并计数
+type AsyncWriter struct {
+	writer       io.Writer
+	dropWhenFull bool
+	queue        chan asyncEntry
+	dropped      atomic.Int64
+	failed       atomic.Int64
+
+	mutex  sync.RWMutex
+	closed bool
+	wait   sync.WaitGroup
+}
+
+func NewAsyncWriter(writer io.Writer, bufferSize int, dropWhenFull bool) *AsyncWriter {
+	if bufferSize <= 0 {
+		bufferSize = 4096
+	}
+	w := &AsyncWriter{
+		writer:       writer,
+		dropWhenFull: dropWhenFull,
+		queue:        make(chan asyncEntry, bufferSize),
+	}
+	w.wait.Add(1)
+	go w.loop()
+	return w
+}
+
+// Write slog 写入后会复用 p，因此需要复制
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if w.closed {
+		return w.writer.Write(p)
+	}
+	entry := asyncEntry{data: append([]byte(nil), p...)}
+	if w.dropWhenFull {
+		select {
+		case w.queue <- entry:
+		default:
+			w.dropped.Add(1)
+		}
+	} else {
+		w.queue <- entry
+	}
+	return len(p), nil
+}
+
+// Flush 等待调用之前写入的内容全部写入下层 writer
+func (w *AsyncWriter) Flush() {
+	w.mutex.RLock()
+	if w.closed {
+		w.mutex.RUnlock()
+		return
+	}
+	done := make(chan struct{})
+	w.queue <- asyncEntry{done: done}
+	w.mutex.RUnlock()
+	<-done
+}
+
+// Close 写完队列中的内容后关闭，之后的写入直接同步写入下层 writer
+func (w *AsyncWriter) Close() error {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mutex.Unlock()
+	w.wait.Wait()
+	if dropped := w.dropped.Load(); dropped > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "async log writer dropped %d records\n", dropped)
+	}
+	if failed := w.failed.Load(); failed > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "async log writer failed to write %d records\n", failed)
+	}
+	return nil
+}
+
+// GetDropped 返回因队列满丢弃的条数
+func (w *AsyncWriter) GetDropped() int64 {
+	return w.dropped.Load()
+}
+
+// GetFailed 返回下层 writer 写入失败的条数
+func (w *AsyncWriter) GetFailed() int64 {
+	return w.failed.Load()
+}
+
+// loop 下层 writer 持续失败时（如网络 sink 不可达）只在进入和恢复时各输出一次
+func (w *AsyncWriter) loop() {
+	defer w.wait.Done()
+	failing := false
+	var failedSince int64
+	for entry := range w.queue {
+		if entry.done != nil {
+			close(entry.done)
+			continue
+		}
+		if _, err := w.writer.Write(entry.data); err != nil {
+			w.failed.Add(1)
+			if !failing {
+				failing = true
+				failedSince = w.failed.Load() - 1
+				_, _ = fmt.Fprintf(os.Stderr, "async log write failed: %v\n", err)
+			}
+			continue
+		}
+		if failing {
+			failing = false
+			_, _ = fmt.Fprintf(os.Stderr, "async log write recovered, %d records failed\n", w.failed.Load()-failedSince)
+		}
+	}
+}
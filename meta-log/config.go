@@ -4,6 +4,7 @@ import (
 	metaerror "meta/meta-error"
 	"meta/meta-flag"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -14,6 +15,19 @@ type Config struct {
 	Cron       string `yaml:"cron"`
 	TimeFormat string `yaml:"time-format"`
 	AddSource  bool   `yaml:"add-source"`
+
+	Level  string            `yaml:"level"`  // 根日志等级，默认 --debug 时为 debug，否则为 info
+	Levels map[string]string `yaml:"levels"` // 各组件的日志等级，如 gorm: debug，未配置的跟随根等级
+
+	RedactKeys []string `yaml:"redact-keys"` // 额外的敏感键名，键名末尾的段与其相同时值在日志中会被替换
+
+	MaxSize    int           `yaml:"max-size"`    // 单个文件最大 MB，超过后切换，0 为不限制
+	MaxAge     time.Duration `yaml:"max-age"`     // 旧文件保留时长，0 为不限制
+	MaxBackups int           `yaml:"max-backups"` // 旧文件保留个数，0 为不限制
+	Compress   bool          `yaml:"compress"`    // 是否 gzip 压缩旧文件
+	Symlink    string        `yaml:"symlink"`     // 指向当前文件的软链接名，为空不创建
+
+	Sinks []*SinkConfig `yaml:"sinks"` // 日志输出，为空时按 stdout 与 path 同步输出
 }
 
 func (config *Config) Parse() error {
@@ -0,0 +1,186 @@
+package metalog
+
+import (
+	"context"
+	"log/slog"
+	metaerror "meta/meta-error"
+	"strings"
+	"sync"
+)
+
+// 各组件的日志名称
+const (
+	LoggerRoot   = "root"
+	LoggerGin    = "gin"
+	LoggerGorm   = "gorm"
+	LoggerFeishu = "feishu"
+	LoggerEvent  = "event"
+	LoggerSocket = "socket"
+)
+
+var rootLevel = new(slog.LevelVar)
+
+var (
+	levelMutex   sync.RWMutex
+	moduleLevels = make(map[string]*slog.LevelVar)
+	loggers      = make(map[string]*slog.Logger)
+)
+
+// ParseLevel 支持 debug、info、warn、error 及 slog 的偏移写法如 info+2
+func ParseLevel(text string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(text))); err != nil {
+		return 0, metaerror.Wrap(err, "invalid log level: %s", text)
+	}
+	return level, nil
+}
+
+// GetLevel 返回组件的日志等级，未单独设置时使用根等级
+func GetLevel(name string) slog.Level {
+	if name == "" || name == LoggerRoot {
+		return rootLevel.Level()
+	}
+	levelMutex.RLock()
+	levelVar, ok := moduleLevels[name]
+	levelMutex.RUnlock()
+	if ok {
+		return levelVar.Level()
+	}
+	return rootLevel.Level()
+}
+
+// SetLevel 运行时修改组件的日志等级，name 为空或 root 时修改根等级
+func SetLevel(name string, level slog.Level) {
+	if name == "" || name == LoggerRoot {
+		rootLevel.Set(level)
+		return
+	}
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	levelVar, ok := moduleLevels[name]
+	if !ok {
+		levelVar = new(slog.LevelVar)
+		moduleLevels[name] = levelVar
+	}
+	levelVar.Set(level)
+}
+
+// ResetLevel 取消组件单独设置的等级，恢复跟随根等级
+func ResetLevel(name string) {
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	delete(moduleLevels, name)
+}
+
+// GetLevels 返回根等级与所有已使用或单独设置的组件的生效等级
+func GetLevels() map[string]string {
+	levelMutex.RLock()
+	defer levelMutex.RUnlock()
+	root := rootLevel.Level().String()
+	result := make(map[string]string, len(loggers)+len(moduleLevels)+1)
+	result[LoggerRoot] = root
+	for name := range loggers {
+		result[name] = root
+	}
+	for name, levelVar := range moduleLevels {
+		result[name] = levelVar.Level().String()
+	}
+	return result
+}
+
+// ApplyLevels 按配置重新设置全部等级，用于配置重载，配置中没有的组件恢复跟随根等级
+func ApplyLevels(root string, levels map[string]string) error {
+	rootValue := rootLevel.Level()
+	if root != "" {
+		level, err := ParseLevel(root)
+		if err != nil {
+			return err
+		}
+		rootValue = level
+	}
+	parsed := make(map[string]slog.Level, len(levels))
+	for name, text := range levels {
+		level, err := ParseLevel(text)
+		if err != nil {
+			return metaerror.Wrap(err, "logger: %s", name)
+		}
+		parsed[name] = level
+	}
+	rootLevel.Set(rootValue)
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	for name := range moduleLevels {
+		if _, ok := parsed[name]; !ok {
+			delete(moduleLevels, name)
+		}
+	}
+	for name, level := range parsed {
+		levelVar, ok := moduleLevels[name]
+		if !ok {
+			levelVar = new(slog.LevelVar)
+			moduleLevels[name] = levelVar
+		}
+		levelVar.Set(level)
+	}
+	return nil
+}
+
+// GetModuleLogger 返回按组件等级过滤的日志，输出仍使用默认日志的 handler
+// 可在 Init 之前获取并保存
+func GetModuleLogger(name string) *slog.Logger {
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	if logger, ok := loggers[name]; ok {
+		return logger
+	}
+	logger := slog.New(&moduleHandler{name: name})
+	loggers[name] = logger
+	return logger
+}
+
+type handlerOp struct {
+	attrs []slog.Attr
+	group string
+}
+
+// moduleHandler 自身决定等级，Handle 时才取默认 handler，因此默认日志替换后仍然生效
+type moduleHandler struct {
+	name string
+	ops  []handlerOp
+}
+
+func (h *moduleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= GetLevel(h.name)
+}
+
+func (h *moduleHandler) Handle(ctx context.Context, record slog.Record) error {
+	handler := slog.Default().Handler()
+	for _, op := range h.ops {
+		if op.group != "" {
+			handler = handler.WithGroup(op.group)
+		} else {
+			handler = handler.WithAttrs(op.attrs)
+		}
+	}
+	return handler.Handle(ctx, record)
+}
+
+func (h *moduleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(handlerOp{attrs: attrs})
+}
+
+func (h *moduleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(handlerOp{group: name})
+}
+
+func (h *moduleHandler) with(op handlerOp) *moduleHandler {
+	ops := make([]handlerOp, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &moduleHandler{name: h.name, ops: append(ops, op)}
+}
@@ -0,0 +1,49 @@
+package metalog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestModuleLevel(t *testing.T) {
+	var buffer bytes.Buffer
+	old := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: rootLevel})))
+	defer slog.SetDefault(old)
+	defer func() {
+		_ = ApplyLevels("info", nil)
+	}()
+
+	if err := ApplyLevels("info", map[string]string{"gorm": "debug"}); err != nil {
+		t.Fatal(err)
+	}
+	gorm := GetModuleLogger("gorm").With("db", "main")
+	gin := GetModuleLogger("gin")
+	gorm.Debug("sql")
+	gin.Debug("request")
+	if !strings.Contains(buffer.String(), "msg=sql db=main") || strings.Contains(buffer.String(), "request") {
+		t.Fatalf("unexpected output: %s", buffer.String())
+	}
+
+	SetLevel("gin", slog.LevelDebug)
+	if !gin.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("gin should be debug after SetLevel")
+	}
+	ResetLevel("gin")
+	if gin.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("gin should follow root after ResetLevel")
+	}
+
+	if err := ApplyLevels("warn", nil); err != nil {
+		t.Fatal(err)
+	}
+	if GetLevel("gorm") != slog.LevelWarn || GetLevels()["gorm"] != "WARN" {
+		t.Errorf("gorm should follow root after reload: %v", GetLevels())
+	}
+	if err := ApplyLevels("", map[string]string{"gorm": "verbose"}); err == nil {
+		t.Error("invalid level should fail")
+	}
+}
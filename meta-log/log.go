@@ -1,23 +1,21 @@
 package metalog
 
 import (
-	"fmt"
-	"io"
 	"log/slog"
 	metaconfig "meta/meta-config"
 	metaerror "meta/meta-error"
 	"meta/meta-flag"
 	metapanic "meta/meta-panic"
-	"os"
-	"path/filepath"
-	"time"
+	metaredact "meta/meta-redact"
+	metatrace "meta/meta-trace"
 
 	"github.com/robfig/cron/v3"
 )
 
 var config *Config
 var logger *slog.Logger
-var logFile *os.File
+var logWriter *RotateWriter
+var logSinks []*sink
 
 // Init 初始化日志系统
 func Init() error {
@@ -29,12 +27,26 @@ func Init() error {
 		Cron:       "0 0/1 ? * ?",
 		TimeFormat: "2006-01-02-15-04-05",
 		AddSource:  false,
+		// 飞书请求量较小，默认输出其 Debug 日志方便排查
+		Levels: map[string]string{LoggerFeishu: "debug"},
 	}
 	err := config.Parse()
 	if err != nil {
 		return err
 	}
 
+	if config.Level == "" {
+		if IsLogDebug() {
+			config.Level = "debug"
+		} else {
+			config.Level = "info"
+		}
+	}
+	err = ApplyLevels(config.Level, config.Levels)
+	if err != nil {
+		return err
+	}
+
 	err = initFileLogger()
 	if err != nil {
 		return err
@@ -54,76 +66,91 @@ func IsLogDebug() bool {
 }
 
 func initFileLogger() error {
-
-	c := cron.New()
-	_, err := c.AddFunc(config.Cron, createNewLogger)
-	if err != nil {
-		slog.Error("Error adding function to cron", "err", err)
-		return err
+	sinkConfigs := config.Sinks
+	if len(sinkConfigs) == 0 {
+		sinkConfigs = getDefaultSinks()
+	}
+	hasFile := false
+	for _, sinkConfig := range sinkConfigs {
+		if sinkConfig.Type == SinkFile {
+			hasFile = true
+		}
+	}
+	if hasFile && config.Path != "" {
+		logWriter = NewRotateWriter(config.Path, metaconfig.GetModuleName(), config.TimeFormat).
+			SetMaxSize(int64(config.MaxSize)*1024*1024).
+			SetRetention(config.MaxAge, config.MaxBackups).
+			SetCompress(config.Compress).
+			SetSymlink(config.Symlink)
+		// 先打开文件，目录不可写时启动即失败
+		if err := logWriter.Rotate(); err != nil {
+			return metaerror.Wrap(err)
+		}
+		if config.Cron != "" {
+			c := cron.New()
+			_, err := c.AddFunc(config.Cron, rotateLogFile)
+			if err != nil {
+				slog.Error("Error adding function to cron", "err", err)
+				return err
+			}
+			c.Start()
+		}
 	}
-	c.Start()
-
-	createLogger()
 
-	return nil
+	return createLogger(sinkConfigs)
 }
 
-func createNewLogger() {
-	if logFile != nil {
-		err := logFile.Close()
-		if err != nil {
-			metapanic.ProcessError(metaerror.Wrap(err))
-			return
-		}
+func rotateLogFile() {
+	if err := logWriter.Rotate(); err != nil {
+		metapanic.ProcessError(metaerror.Wrap(err))
 	}
-	createLogger()
 }
 
-func createLogger() {
-	logDir := config.Path
-	var logFile *os.File
-	if logDir != "" {
-		logFile = createLogFile(logDir)
-	}
-	var ioWriter io.Writer
-	if logFile != nil && config.Stdout {
-		ioWriter = io.MultiWriter(logFile, os.Stdout)
-	} else if logFile != nil {
-		ioWriter = logFile
-	} else if config.Stdout {
-		ioWriter = os.Stdout
-	} else {
-		ioWriter = io.Discard
-	}
+// createLogger handler 只创建一次，文件切换在 RotateWriter 内部完成
+func createLogger(sinkConfigs []*SinkConfig) error {
 	options := &slog.HandlerOptions{}
 	options.AddSource = config.AddSource
-	if IsLogDebug() {
-		options.Level = slog.LevelDebug
-	} else {
-		options.Level = slog.LevelInfo
-	}
-	if metaflag.IsDebug() {
-		logger = slog.New(slog.NewTextHandler(ioWriter, options))
-	} else {
-		logger = slog.New(slog.NewJSONHandler(ioWriter, options))
+	var sinks []*sink
+	for _, sinkConfig := range sinkConfigs {
+		s, err := newSink(sinkConfig, options)
+		if err != nil {
+			for _, created := range sinks {
+				_ = created.close()
+			}
+			return err
+		}
+		sinks = append(sinks, s)
 	}
+	logSinks = sinks
+	metaredact.AddKeys(config.RedactKeys...)
+	logger = slog.New(metatrace.NewHandler(metaredact.NewHandler(&multiHandler{level: rootLevel, sinks: sinks})))
 	slog.SetDefault(logger)
+	return nil
 }
 
-func createLogFile(logDir string) *os.File {
-	currentTime := time.Now().Format(config.TimeFormat)
-	fileName := fmt.Sprintf("%s_%s.log", metaconfig.GetModuleName(), currentTime)
-	filePath := filepath.Join(logDir, fileName)
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		panic(fmt.Sprintf("failed to create log directory: %v", err))
-	}
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		panic(fmt.Sprintf("failed to open log file: %v", err))
+func GetLogger() *slog.Logger {
+	return logger
+}
+
+// Flush 等待异步输出写完当前缓冲
+func Flush() {
+	for _, s := range logSinks {
+		s.flush()
 	}
-	return file
 }
 
-func GetLogger() *slog.Logger {
-	return logger
+// Close 写完异步缓冲后关闭各输出与日志文件，并等待旧文件处理完成
+func Close() error {
+	var lastErr error
+	for _, s := range logSinks {
+		if err := s.close(); err != nil {
+			lastErr = err
+		}
+	}
+	if logWriter != nil {
+		if err := logWriter.Close(); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
 }
@@ -0,0 +1,65 @@
+package metalog
+
+import (
+	metaerror "meta/meta-error"
+	"net"
+	"sync"
+	"time"
+)
+
+const networkRetryInterval = 5 * time.Second
+
+// NetworkWriter 以 JSON 行的形式发送到 tcp 或 udp 地址，断开后在下次写入时重连
+// 连接不可用期间的日志会被丢弃，不会阻塞业务
+type NetworkWriter struct {
+	network string
+	address string
+	timeout time.Duration
+
+	mutex     sync.Mutex
+	conn      net.Conn
+	lastRetry time.Time
+}
+
+func NewNetworkWriter(network string, address string) *NetworkWriter {
+	return &NetworkWriter{
+		network: network,
+		address: address,
+		timeout: 3 * time.Second,
+	}
+}
+
+func (w *NetworkWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.conn == nil {
+		if time.Since(w.lastRetry) < networkRetryInterval {
+			return 0, metaerror.New("log connection unavailable: %s", w.address)
+		}
+		w.lastRetry = time.Now()
+		conn, err := net.DialTimeout(w.network, w.address, w.timeout)
+		if err != nil {
+			return 0, metaerror.Wrap(err, "dial log address failed: %s", w.address)
+		}
+		w.conn = conn
+	}
+	_ = w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+	n, err := w.conn.Write(p)
+	if err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		return n, metaerror.Wrap(err, "write log to %s failed", w.address)
+	}
+	return n, nil
+}
+
+func (w *NetworkWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,358 @@
+package metalog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	metaerror "meta/meta-error"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const compressSuffix = ".gz"
+
+// RotateWriter 按大小或定时切换日志文件，切换在写锁内完成，不会丢失或交错日志
+// 切换出的旧文件在后台压缩与清理
+type RotateWriter struct {
+	dir        string
+	prefix     string
+	timeFormat string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	symlink    string
+	now        func() time.Time
+
+	mutex    sync.Mutex
+	file     *os.File
+	size     int64
+	millChan chan struct{} // 后台清理通知，受 mutex 保护，Close 后置空，下次切换时重新创建
+	millDone chan struct{} // 当前后台协程退出时关闭
+}
+
+func NewRotateWriter(dir string, prefix string, timeFormat string) *RotateWriter {
+	return &RotateWriter{
+		dir:        dir,
+		prefix:     prefix,
+		timeFormat: timeFormat,
+		now:        time.Now,
+	}
+}
+
+// SetMaxSize 单个文件超过 maxSize 字节后切换，0 为不限制
+func (w *RotateWriter) SetMaxSize(maxSize int64) *RotateWriter {
+	w.maxSize = maxSize
+	return w
+}
+
+// SetRetention 删除超过 maxAge 或超出 maxBackups 个数的旧文件，0 为不限制
+func (w *RotateWriter) SetRetention(maxAge time.Duration, maxBackups int) *RotateWriter {
+	w.maxAge = maxAge
+	w.maxBackups = maxBackups
+	return w
+}
+
+func (w *RotateWriter) SetCompress(compress bool) *RotateWriter {
+	w.compress = compress
+	return w
+}
+
+// SetSymlink 在日志目录下创建指向当前文件的软链接，为空不创建
+func (w *RotateWriter) SetSymlink(symlink string) *RotateWriter {
+	w.symlink = symlink
+	return w
+}
+
+func (w *RotateWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.file == nil {
+		if err := w.openNew(); err != nil {
+			return 0, err
+		}
+	} else if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate 立即切换到新文件
+func (w *RotateWriter) Rotate() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.rotate()
+}
+
+// Close 之后再写入会打开新文件
+func (w *RotateWriter) Close() error {
+	w.mutex.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	millDone := w.millDone
+	if w.millChan != nil {
+		close(w.millChan)
+		w.millChan = nil
+		w.millDone = nil
+	}
+	w.mutex.Unlock()
+	if millDone != nil {
+		<-millDone
+	}
+	return err
+}
+
+// GetFileName 返回当前文件路径
+func (w *RotateWriter) GetFileName() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.file == nil {
+		return ""
+	}
+	return w.file.Name()
+}
+
+func (w *RotateWriter) rotate() error {
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "close log file failed: %v\n", err)
+		}
+		w.file = nil
+	}
+	if err := w.openNew(); err != nil {
+		return err
+	}
+	w.mill()
+	return nil
+}
+
+func (w *RotateWriter) openNew() error {
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		return metaerror.Wrap(err, "failed to create log directory: %s", w.dir)
+	}
+	filePath := w.newFilePath()
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return metaerror.Wrap(err, "failed to open log file: %s", filePath)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return metaerror.Wrap(err, "failed to stat log file: %s", filePath)
+	}
+	w.file = file
+	w.size = info.Size()
+	if w.symlink != "" {
+		w.updateSymlink(filePath)
+	}
+	return nil
+}
+
+// newFilePath 同一时间内多次切换时追加序号，避免写回已切换的文件
+func (w *RotateWriter) newFilePath() string {
+	base := fmt.Sprintf("%s_%s", w.prefix, w.now().Format(w.timeFormat))
+	filePath := filepath.Join(w.dir, base+".log")
+	for i := 1; ; i++ {
+		if !w.exists(filePath) {
+			return filePath
+		}
+		filePath = filepath.Join(w.dir, fmt.Sprintf("%s.%d.log", base, i))
+	}
+}
+
+func (w *RotateWriter) exists(filePath string) bool {
+	if _, err := os.Lstat(filePath); err == nil {
+		return true
+	}
+	if _, err := os.Lstat(filePath + compressSuffix); err == nil {
+		return true
+	}
+	return false
+}
+
+// updateSymlink 先创建临时链接再重命名，保证链接始终可用
+func (w *RotateWriter) updateSymlink(filePath string) {
+	linkPath := filepath.Join(w.dir, w.symlink)
+	tmpPath := linkPath + ".tmp"
+	_ = os.Remove(tmpPath)
+	if err := os.Symlink(filepath.Base(filePath), tmpPath); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "create log symlink failed: %v\n", err)
+		return
+	}
+	if err := os.Rename(tmpPath, linkPath); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "rename log symlink failed: %v\n", err)
+	}
+}
+
+// mill 通知后台协程压缩与清理旧文件，已有待处理的通知时直接返回，需持有 mutex
+func (w *RotateWriter) mill() {
+	if !w.compress && w.maxAge <= 0 && w.maxBackups <= 0 {
+		return
+	}
+	if w.millChan == nil {
+		w.millChan = make(chan struct{}, 1)
+		w.millDone = make(chan struct{})
+		go w.millLoop(w.millChan, w.millDone)
+	}
+	select {
+	case w.millChan <- struct{}{}:
+	default:
+	}
+}
+
+func (w *RotateWriter) millLoop(millChan chan struct{}, millDone chan struct{}) {
+	defer close(millDone)
+	for range millChan {
+		if err := w.millRun(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "process old log files failed: %v\n", err)
+		}
+	}
+}
+
+type logFileInfo struct {
+	path    string
+	modTime time.Time
+}
+
+func (w *RotateWriter) millRun() error {
+	files, err := w.oldFiles()
+	if err != nil {
+		return err
+	}
+	var removes []logFileInfo
+	var remains []logFileInfo
+	for i, file := range files {
+		if w.maxBackups > 0 && i >= w.maxBackups {
+			removes = append(removes, file)
+			continue
+		}
+		if w.maxAge > 0 && w.now().Sub(file.modTime) > w.maxAge {
+			removes = append(removes, file)
+			continue
+		}
+		remains = append(remains, file)
+	}
+	var lastErr error
+	for _, file := range removes {
+		if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+			lastErr = err
+		}
+	}
+	if w.compress {
+		for _, file := range remains {
+			if strings.HasSuffix(file.path, compressSuffix) {
+				continue
+			}
+			if err := compressFile(file.path); err != nil {
+				lastErr = err
+			}
+		}
+	}
+	return lastErr
+}
+
+// oldFiles 按修改时间从新到旧返回除当前文件外的日志文件
+func (w *RotateWriter) oldFiles() ([]logFileInfo, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, err
+	}
+	// 读取目录之后再取当前文件，期间切换出的新文件不会出现在列表中
+	current := w.GetFileName()
+	var files []logFileInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || !w.isLogFileName(name) {
+			continue
+		}
+		path := filepath.Join(w.dir, name)
+		if path == current {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, logFileInfo{path: path, modTime: info.ModTime()})
+	}
+	sort.Slice(
+		files, func(i, j int) bool {
+			return files[i].modTime.After(files[j].modTime)
+		},
+	)
+	return files, nil
+}
+
+// isLogFileName 只匹配本 writer 生成的 {prefix}_{时间}[.序号].log[.gz]
+// 避免共享目录时误处理前缀相同的其他模块日志，如 app 与 app_worker
+func (w *RotateWriter) isLogFileName(name string) bool {
+	name = strings.TrimSuffix(name, compressSuffix)
+	if !strings.HasSuffix(name, ".log") || !strings.HasPrefix(name, w.prefix+"_") {
+		return false
+	}
+	timePart := strings.TrimSuffix(strings.TrimPrefix(name, w.prefix+"_"), ".log")
+	if _, err := time.Parse(w.timeFormat, timePart); err == nil {
+		return true
+	}
+	i := strings.LastIndexByte(timePart, '.')
+	if i < 0 {
+		return false
+	}
+	if _, err := strconv.Atoi(timePart[i+1:]); err != nil {
+		return false
+	}
+	_, err := time.Parse(w.timeFormat, timePart[:i])
+	return err == nil
+}
+
+// compressFile 先写入临时文件，完成后再替换，避免留下不完整的压缩文件
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	tmpPath := path + compressSuffix + ".tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(dst)
+	if _, err = io.Copy(writer, src); err == nil {
+		err = writer.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err = os.Chtimes(tmpPath, info.ModTime(), info.ModTime()); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err = os.Rename(tmpPath, path+compressSuffix); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return os.Remove(path)
+}
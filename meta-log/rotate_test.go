@@ -0,0 +1,141 @@
+package metalog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestWriter(t *testing.T) (*RotateWriter, string) {
+	dir := t.TempDir()
+	writer := NewRotateWriter(dir, "test", "2006-01-02-15-04-05")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	writer.now = func() time.Time { return now }
+	return writer, dir
+}
+
+func readLogs(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		file, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var reader io.Reader = file
+		if strings.HasSuffix(entry.Name(), compressSuffix) {
+			if reader, err = gzip.NewReader(file); err != nil {
+				t.Fatal(err)
+			}
+		}
+		data, err := io.ReadAll(reader)
+		_ = file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+func TestRotateWriterSize(t *testing.T) {
+	writer, dir := newTestWriter(t)
+	writer.SetMaxSize(100).SetCompress(true).SetSymlink("current.log")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_, _ = fmt.Fprintf(writer, "record-%d-%02d-xxxxxxxxxxxxxxxxxxxx\n", i, j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	current := writer.GetFileName()
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := readLogs(t, dir)
+	if len(lines) != 400 {
+		t.Fatalf("expected 400 records, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "record-") || len(line) != len("record-0-00-xxxxxxxxxxxxxxxxxxxx") {
+			t.Fatalf("interleaved record: %q", line)
+		}
+	}
+	target, err := os.Readlink(filepath.Join(dir, "current.log"))
+	if err != nil || target != filepath.Base(current) {
+		t.Fatalf("symlink should point to current file: %s %v", target, err)
+	}
+}
+
+func TestRotateWriterRetention(t *testing.T) {
+	writer, dir := newTestWriter(t)
+	writer.SetRetention(0, 2)
+	for i := 0; i < 5; i++ {
+		_, _ = writer.Write([]byte("line\n"))
+		if err := writer.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+		// 保证修改时间有先后
+		past := time.Now().Add(time.Duration(i-10) * time.Second)
+		_ = os.Chtimes(writer.GetFileName(), past, past)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) > 3 {
+		t.Fatalf("expected at most 3 files, got %d", len(entries))
+	}
+}
+
+func TestRotateWriterKeepOtherFiles(t *testing.T) {
+	writer, dir := newTestWriter(t)
+	writer.SetRetention(0, 1)
+	other := filepath.Join(dir, "test_worker_2024-01-01-00-00-00.log")
+	if err := os.WriteFile(other, []byte("other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rotateAll := func() {
+		for i := 0; i < 3; i++ {
+			_, _ = writer.Write([]byte("line\n"))
+			if err := writer.Rotate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rotateAll()
+	// Close 之后再次切换，清理仍需生效
+	rotateAll()
+	if _, err := os.Stat(other); err != nil {
+		t.Fatalf("other file removed: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) > 3 {
+		t.Fatalf("expected at most 3 files, got %d", len(entries))
+	}
+}
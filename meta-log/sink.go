@@ -0,0 +1,185 @@
+package metalog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	metaerror "meta/meta-error"
+	"os"
+)
+
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkTcp    = "tcp"
+	SinkUdp    = "udp"
+
+	FormatJson = "json"
+	FormatText = "text"
+)
+
+type SinkConfig struct {
+	Type         string `yaml:"type"`           // stdout、file、tcp、udp，file 使用 path 与轮转配置
+	Format       string `yaml:"format"`         // json 或 text，默认 --debug 时为 text，否则为 json，tcp 与 udp 固定为 json
+	Level        string `yaml:"level"`          // 该输出的最低等级，为空时只受根等级与组件等级控制
+	Address      string `yaml:"address"`        // tcp、udp 的地址，如 127.0.0.1:5170
+	Async        bool   `yaml:"async"`          // 异步写入，tcp 与 udp 总是异步
+	BufferSize   int    `yaml:"buffer-size"`    // 异步缓冲的日志条数，默认为 4096
+	DropWhenFull bool   `yaml:"drop-when-full"` // 缓冲满时丢弃而非阻塞，tcp 与 udp 总是丢弃
+}
+
+// sink 一个日志输出
+type sink struct {
+	handler  slog.Handler
+	minLevel slog.Level
+	filtered bool
+	async    *AsyncWriter
+	closer   io.Closer
+}
+
+// getDefaultSinks 未配置 sinks 时按 stdout 与 path 生成，保持同步写入
+func getDefaultSinks() []*SinkConfig {
+	var sinks []*SinkConfig
+	if config.Path != "" {
+		sinks = append(sinks, &SinkConfig{Type: SinkFile})
+	}
+	if config.Stdout {
+		sinks = append(sinks, &SinkConfig{Type: SinkStdout})
+	}
+	return sinks
+}
+
+func newSink(sinkConfig *SinkConfig, options *slog.HandlerOptions) (*sink, error) {
+	result := &sink{}
+	if sinkConfig.Level != "" {
+		level, err := ParseLevel(sinkConfig.Level)
+		if err != nil {
+			return nil, err
+		}
+		result.minLevel = level
+		result.filtered = true
+	}
+	format := sinkConfig.Format
+	async := sinkConfig.Async
+	dropWhenFull := sinkConfig.DropWhenFull
+	var writer io.Writer
+	switch sinkConfig.Type {
+	case SinkStdout:
+		writer = os.Stdout
+	case SinkFile:
+		if logWriter == nil {
+			return nil, metaerror.New("file log sink requires path")
+		}
+		writer = logWriter
+	case SinkTcp, SinkUdp:
+		if sinkConfig.Address == "" {
+			return nil, metaerror.New("%s log sink requires address", sinkConfig.Type)
+		}
+		networkWriter := NewNetworkWriter(sinkConfig.Type, sinkConfig.Address)
+		writer = networkWriter
+		result.closer = networkWriter
+		format = FormatJson
+		async = true
+		dropWhenFull = true
+	default:
+		return nil, metaerror.New("unknown log sink type: %s", sinkConfig.Type)
+	}
+	if async {
+		result.async = NewAsyncWriter(writer, sinkConfig.BufferSize, dropWhenFull)
+		writer = result.async
+	}
+	if format == "" {
+		if IsLogDebug() {
+			format = FormatText
+		} else {
+			format = FormatJson
+		}
+	}
+	switch format {
+	case FormatJson:
+		result.handler = slog.NewJSONHandler(writer, options)
+	case FormatText:
+		result.handler = slog.NewTextHandler(writer, options)
+	default:
+		return nil, metaerror.New("unknown log format: %s", format)
+	}
+	return result, nil
+}
+
+func (s *sink) enabled(level slog.Level) bool {
+	return !s.filtered || level >= s.minLevel
+}
+
+func (s *sink) with(handler slog.Handler) *sink {
+	copied := *s
+	copied.handler = handler
+	return &copied
+}
+
+// flush 异步输出写完缓冲后返回
+func (s *sink) flush() {
+	if s.async != nil {
+		s.async.Flush()
+	}
+}
+
+func (s *sink) close() error {
+	var err error
+	if s.async != nil {
+		err = s.async.Close()
+	}
+	if s.closer != nil {
+		if closeErr := s.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// multiHandler 按根等级过滤后分发给各输出，各输出再按自身等级过滤
+type multiHandler struct {
+	level slog.Leveler
+	sinks []*sink
+}
+
+func (h *multiHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if level < h.level.Level() {
+		return false
+	}
+	for _, s := range h.sinks {
+		if s.enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle 组件日志会绕过 Enabled 直接调用，因此根等级只在 Enabled 中判断
+func (h *multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var lastErr error
+	for _, s := range h.sinks {
+		if !s.enabled(record.Level) {
+			continue
+		}
+		if err := s.handler.Handle(ctx, record.Clone()); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (h *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	sinks := make([]*sink, len(h.sinks))
+	for i, s := range h.sinks {
+		sinks[i] = s.with(s.handler.WithAttrs(attrs))
+	}
+	return &multiHandler{level: h.level, sinks: sinks}
+}
+
+func (h *multiHandler) WithGroup(name string) slog.Handler {
+	sinks := make([]*sink, len(h.sinks))
+	for i, s := range h.sinks {
+		sinks[i] = s.with(s.handler.WithGroup(name))
+	}
+	return &multiHandler{level: h.level, sinks: sinks}
+}
@@ -0,0 +1,159 @@
+package metalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type slowBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *slowBuffer) Write(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *slowBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.String()
+}
+
+func TestAsyncWriterFlush(t *testing.T) {
+	buffer := &slowBuffer{}
+	writer := NewAsyncWriter(buffer, 16, false)
+	logger := slog.New(slog.NewJSONHandler(writer, nil))
+	for i := 0; i < 50; i++ {
+		logger.Info("record", "i", i)
+	}
+	writer.Flush()
+	if lines := strings.Count(buffer.String(), "\n"); lines != 50 {
+		t.Fatalf("expected 50 lines after flush, got %d", lines)
+	}
+	_ = writer.Close()
+	logger.Info("after close")
+	if !strings.Contains(buffer.String(), "after close") {
+		t.Error("writes after close should be synchronous")
+	}
+}
+
+type failingWriter struct {
+	fail bool
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errors.New("connection refused")
+	}
+	return len(p), nil
+}
+
+func TestAsyncWriterReportFailureOnce(t *testing.T) {
+	reader, pipe, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = pipe
+	defer func() { os.Stderr = stderr }()
+
+	target := &failingWriter{fail: true}
+	writer := NewAsyncWriter(target, 16, false)
+	for i := 0; i < 10; i++ {
+		_, _ = writer.Write([]byte("record\n"))
+	}
+	writer.Flush()
+	target.fail = false
+	_, _ = writer.Write([]byte("record\n"))
+	_ = writer.Close()
+	_ = pipe.Close()
+	os.Stderr = stderr
+
+	output, _ := io.ReadAll(reader)
+	if lines := strings.Count(string(output), "\n"); lines != 3 {
+		t.Fatalf("expected 3 stderr lines, got %d: %s", lines, output)
+	}
+	if !strings.Contains(string(output), "10 records failed") {
+		t.Errorf("unexpected output: %s", output)
+	}
+	if failed := writer.GetFailed(); failed != 10 {
+		t.Errorf("expected 10 failed, got %d", failed)
+	}
+}
+
+func TestMultiHandlerLevels(t *testing.T) {
+	var text, jsonBuffer bytes.Buffer
+	level := new(slog.LevelVar)
+	handler := &multiHandler{
+		level: level,
+		sinks: []*sink{
+			{handler: slog.NewTextHandler(&text, nil)},
+			{handler: slog.NewJSONHandler(&jsonBuffer, nil), minLevel: slog.LevelWarn, filtered: true},
+		},
+	}
+	logger := slog.New(handler).With("module", "test")
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	if strings.Contains(text.String(), "debug") || !strings.Contains(text.String(), "msg=info module=test") {
+		t.Errorf("unexpected text output: %s", text.String())
+	}
+	if strings.Contains(jsonBuffer.String(), "info") || !strings.Contains(jsonBuffer.String(), `"msg":"warn","module":"test"`) {
+		t.Errorf("unexpected json output: %s", jsonBuffer.String())
+	}
+}
+
+func TestNetworkSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = listener.Close()
+	}()
+	received := make(chan map[string]any, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var record map[string]any
+			if json.Unmarshal(scanner.Bytes(), &record) == nil {
+				received <- record
+			}
+		}
+	}()
+
+	s, err := newSink(&SinkConfig{Type: SinkTcp, Address: listener.Addr().String()}, &slog.HandlerOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(&multiHandler{level: new(slog.LevelVar), sinks: []*sink{s}})
+	logger.Info("network", "key", "value")
+	s.flush()
+	select {
+	case record := <-received:
+		if record["msg"] != "network" || record["key"] != "value" {
+			t.Errorf("unexpected record: %v", record)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("record not received")
+	}
+	_ = s.close()
+}
@@ -0,0 +1,6 @@
+package metametrics
+
+type Config struct {
+	Port int32  `yaml:"port"` // 独立监听的端口，0 表示不独立监听，可通过 Http 子系统的 metrics-path 提供
+	Path string `yaml:"path"` // 抓取路径，默认为 /metrics
+}
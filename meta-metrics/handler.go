@@ -0,0 +1,20 @@
+package metametrics
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler 提供 Prometheus 抓取的 HTTP 接口
+func Handler() http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			if err := WriteText(w); err != nil {
+				slog.Warn("write metrics failed", "err", err)
+			}
+		},
+	)
+}
@@ -0,0 +1,205 @@
+package metametrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type metricType string
+
+const (
+	metricTypeCounter   metricType = "counter"
+	metricTypeGauge     metricType = "gauge"
+	metricTypeHistogram metricType = "histogram"
+)
+
+// DefaultBuckets 默认的耗时分布区间，单位为秒
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// labelSeparator 不会出现在正常标签值中，用于拼接标签组合作为 key
+const labelSeparator = "\xff"
+
+type vec[T any] struct {
+	name       string
+	help       string
+	metricType metricType
+	labelNames []string
+	newSeries  func() T
+
+	mutex  sync.RWMutex
+	series map[string]*seriesEntry[T]
+}
+
+type seriesEntry[T any] struct {
+	labelValues []string
+	value       T
+}
+
+func newVec[T any](name string, help string, metricType metricType, labelNames []string, newSeries func() T) *vec[T] {
+	return &vec[T]{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		labelNames: labelNames,
+		newSeries:  newSeries,
+		series:     make(map[string]*seriesEntry[T]),
+	}
+}
+
+// with 按标签值获取序列，标签值数量不足时以空字符串补齐
+func (v *vec[T]) with(labelValues []string) T {
+	if len(labelValues) != len(v.labelNames) {
+		values := make([]string, len(v.labelNames))
+		copy(values, labelValues)
+		labelValues = values
+	}
+	key := strings.Join(labelValues, labelSeparator)
+	v.mutex.RLock()
+	entry, ok := v.series[key]
+	v.mutex.RUnlock()
+	if ok {
+		return entry.value
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if entry, ok = v.series[key]; ok {
+		return entry.value
+	}
+	entry = &seriesEntry[T]{
+		labelValues: append([]string{}, labelValues...),
+		value:       v.newSeries(),
+	}
+	v.series[key] = entry
+	return entry.value
+}
+
+// sortedSeries 按标签值排序，保证输出稳定
+func (v *vec[T]) sortedSeries() []*seriesEntry[T] {
+	v.mutex.RLock()
+	result := make([]*seriesEntry[T], 0, len(v.series))
+	for _, entry := range v.series {
+		result = append(result, entry)
+	}
+	v.mutex.RUnlock()
+	sort.Slice(
+		result, func(i, j int) bool {
+			return strings.Join(result[i].labelValues, labelSeparator) < strings.Join(result[j].labelValues, labelSeparator)
+		},
+	)
+	return result
+}
+
+// atomicFloat 以 uint64 存储的 float64，支持并发累加
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) Add(delta float64) {
+	for {
+		old := f.bits.Load()
+		value := math.Float64frombits(old) + delta
+		if f.bits.CompareAndSwap(old, math.Float64bits(value)) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) Set(value float64) {
+	f.bits.Store(math.Float64bits(value))
+}
+
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+type Counter struct {
+	value atomicFloat
+}
+
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Add delta 必须为非负数，负数会被忽略
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.value.Add(delta)
+}
+
+func (c *Counter) Value() float64 {
+	return c.value.Load()
+}
+
+type Gauge struct {
+	value atomicFloat
+}
+
+func (g *Gauge) Set(value float64) {
+	g.value.Set(value)
+}
+
+func (g *Gauge) Add(delta float64) {
+	g.value.Add(delta)
+}
+
+func (g *Gauge) Inc() {
+	g.value.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.value.Add(-1)
+}
+
+func (g *Gauge) Value() float64 {
+	return g.value.Load()
+}
+
+type Histogram struct {
+	buckets []float64
+	counts  []atomic.Uint64 // 每个区间的数量，最后一个为 +Inf
+	sum     atomicFloat
+	count   atomic.Uint64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		buckets: buckets,
+		counts:  make([]atomic.Uint64, len(buckets)+1),
+	}
+}
+
+func (h *Histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.buckets, value)
+	h.counts[i].Add(1)
+	h.sum.Add(value)
+	h.count.Add(1)
+}
+
+type CounterVec struct {
+	*vec[*Counter]
+}
+
+func (v *CounterVec) WithLabelValues(labelValues ...string) *Counter {
+	return v.with(labelValues)
+}
+
+type GaugeVec struct {
+	*vec[*Gauge]
+}
+
+func (v *GaugeVec) WithLabelValues(labelValues ...string) *Gauge {
+	return v.with(labelValues)
+}
+
+type HistogramVec struct {
+	*vec[*Histogram]
+}
+
+func (v *HistogramVec) WithLabelValues(labelValues ...string) *Histogram {
+	return v.with(labelValues)
+}
@@ -0,0 +1,48 @@
+package metametrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	counter := NewCounterVec("test_requests_total", "Total requests.", "method", "path")
+	counter.WithLabelValues("GET", "/a\"b").Inc()
+	counter.WithLabelValues("GET", "/a\"b").Add(2)
+	counter.WithLabelValues("POST", "/c").Add(-1)
+	if NewCounterVec("test_requests_total", "") != counter {
+		t.Fatal("duplicate register should return existing metric")
+	}
+
+	gauge := NewGauge("test_connections", "")
+	gauge.Inc()
+	gauge.Inc()
+	gauge.Dec()
+
+	histogram := NewHistogramVec("test_duration_seconds", "", []float64{0.1, 1}, "op")
+	histogram.WithLabelValues("read").Observe(0.05)
+	histogram.WithLabelValues("read").Observe(0.5)
+	histogram.WithLabelValues("read").Observe(3)
+
+	var buffer bytes.Buffer
+	if err := WriteText(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	output := buffer.String()
+	for _, want := range []string{
+		"# HELP test_requests_total Total requests.\n# TYPE test_requests_total counter\n",
+		`test_requests_total{method="GET",path="/a\"b"} 3` + "\n",
+		`test_requests_total{method="POST",path="/c"} 0` + "\n",
+		"# TYPE test_connections gauge\ntest_connections 1\n",
+		`test_duration_seconds_bucket{op="read",le="0.1"} 1` + "\n",
+		`test_duration_seconds_bucket{op="read",le="1"} 2` + "\n",
+		`test_duration_seconds_bucket{op="read",le="+Inf"} 3` + "\n",
+		`test_duration_seconds_sum{op="read"} 3.55` + "\n",
+		`test_duration_seconds_count{op="read"} 3` + "\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
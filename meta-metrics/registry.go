@@ -0,0 +1,210 @@
+package metametrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type collector interface {
+	getName() string
+	getType() metricType
+	write(w *bufio.Writer)
+}
+
+var (
+	registryMutex sync.Mutex
+	registry      = make(map[string]collector)
+)
+
+// register 同名指标重复注册时返回已注册的指标，类型不一致时 panic
+func register[T collector](name string, create func() T) T {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if existing, ok := registry[name]; ok {
+		result, ok := existing.(T)
+		if !ok {
+			panic(fmt.Sprintf("metric %s already registered as %s", name, existing.getType()))
+		}
+		return result
+	}
+	result := create()
+	registry[name] = result
+	return result
+}
+
+// NewCounterVec 创建只增不减的计数器
+func NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	return register(
+		name, func() *CounterVec {
+			return &CounterVec{newVec(name, help, metricTypeCounter, labelNames, func() *Counter { return &Counter{} })}
+		},
+	)
+}
+
+// NewGaugeVec 创建可增可减的仪表
+func NewGaugeVec(name string, help string, labelNames ...string) *GaugeVec {
+	return register(
+		name, func() *GaugeVec {
+			return &GaugeVec{newVec(name, help, metricTypeGauge, labelNames, func() *Gauge { return &Gauge{} })}
+		},
+	)
+}
+
+// NewHistogramVec buckets 为空时使用 DefaultBuckets
+func NewHistogramVec(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+	return register(
+		name, func() *HistogramVec {
+			return &HistogramVec{
+				newVec(
+					name, help, metricTypeHistogram, labelNames, func() *Histogram {
+						return newHistogram(buckets)
+					},
+				),
+			}
+		},
+	)
+}
+
+func NewCounter(name string, help string) *Counter {
+	return NewCounterVec(name, help).WithLabelValues()
+}
+
+func NewGauge(name string, help string) *Gauge {
+	return NewGaugeVec(name, help).WithLabelValues()
+}
+
+func NewHistogram(name string, help string, buckets []float64) *Histogram {
+	return NewHistogramVec(name, help, buckets).WithLabelValues()
+}
+
+// WriteText 以 Prometheus 文本格式输出所有指标
+func WriteText(w io.Writer) error {
+	registryMutex.Lock()
+	collectors := make([]collector, 0, len(registry))
+	for _, c := range registry {
+		collectors = append(collectors, c)
+	}
+	registryMutex.Unlock()
+	sort.Slice(
+		collectors, func(i, j int) bool {
+			return collectors[i].getName() < collectors[j].getName()
+		},
+	)
+	writer := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(writer)
+	}
+	return writer.Flush()
+}
+
+func (v *vec[T]) getName() string {
+	return v.name
+}
+
+func (v *vec[T]) getType() metricType {
+	return v.metricType
+}
+
+func (v *vec[T]) writeHeader(w *bufio.Writer) {
+	if v.help != "" {
+		_, _ = fmt.Fprintf(w, "# HELP %s %s\n", v.name, escapeHelp(v.help))
+	}
+	_, _ = fmt.Fprintf(w, "# TYPE %s %s\n", v.name, v.metricType)
+}
+
+func (v *CounterVec) write(w *bufio.Writer) {
+	v.writeHeader(w)
+	for _, entry := range v.sortedSeries() {
+		writeSample(w, v.name, v.labelNames, entry.labelValues, "", "", entry.value.Value())
+	}
+}
+
+func (v *GaugeVec) write(w *bufio.Writer) {
+	v.writeHeader(w)
+	for _, entry := range v.sortedSeries() {
+		writeSample(w, v.name, v.labelNames, entry.labelValues, "", "", entry.value.Value())
+	}
+}
+
+func (v *HistogramVec) write(w *bufio.Writer) {
+	v.writeHeader(w)
+	for _, entry := range v.sortedSeries() {
+		h := entry.value
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += h.counts[i].Load()
+			writeSample(w, v.name+"_bucket", v.labelNames, entry.labelValues, "le", formatFloat(bound), float64(cumulative))
+		}
+		cumulative += h.counts[len(h.buckets)].Load()
+		writeSample(w, v.name+"_bucket", v.labelNames, entry.labelValues, "le", "+Inf", float64(cumulative))
+		writeSample(w, v.name+"_sum", v.labelNames, entry.labelValues, "", "", h.sum.Load())
+		writeSample(w, v.name+"_count", v.labelNames, entry.labelValues, "", "", float64(h.count.Load()))
+	}
+}
+
+func writeSample(
+	w *bufio.Writer,
+	name string,
+	labelNames []string,
+	labelValues []string,
+	extraName string,
+	extraValue string,
+	value float64,
+) {
+	_, _ = w.WriteString(name)
+	if len(labelNames) > 0 || extraName != "" {
+		_ = w.WriteByte('{')
+		for i, labelName := range labelNames {
+			if i > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = fmt.Fprintf(w, "%s=\"%s\"", labelName, escapeLabelValue(labelValues[i]))
+		}
+		if extraName != "" {
+			if len(labelNames) > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = fmt.Fprintf(w, "%s=\"%s\"", extraName, extraValue)
+		}
+		_ = w.WriteByte('}')
+	}
+	_ = w.WriteByte(' ')
+	_, _ = w.WriteString(formatFloat(value))
+	_ = w.WriteByte('\n')
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpReplacer  = strings.NewReplacer("\\", "\\\\", "\n", "\\n")
+	labelReplacer = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\"", "\\\"")
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelReplacer.Replace(s)
+}
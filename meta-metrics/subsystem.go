@@ -0,0 +1,84 @@
+package metametrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"meta/engine"
+	metaerror "meta/meta-error"
+	"meta/metaroutine"
+	"meta/subsystem"
+	"net"
+	"net/http"
+	"time"
+)
+
+type Subsystem struct {
+	subsystem.Subsystem
+	GetConfig func() *Config
+
+	server *http.Server
+}
+
+func GetSubsystem() *Subsystem {
+	if thisSubsystem := engine.GetSubsystem[*Subsystem](); thisSubsystem != nil {
+		return thisSubsystem.(*Subsystem)
+	}
+	return nil
+}
+
+func (s *Subsystem) GetName() string {
+	return "Metrics"
+}
+
+func (s *Subsystem) Start() error {
+	config := &Config{}
+	if s.GetConfig != nil {
+		if c := s.GetConfig(); c != nil {
+			config = c
+		}
+	}
+	if config.Path == "" {
+		config.Path = "/metrics"
+	}
+	if config.Port == 0 {
+		return nil
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", config.Port))
+	if err != nil {
+		return metaerror.Wrap(err, "metrics listen failed, port:%d", config.Port)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(config.Path, Handler())
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	slog.Info("Metrics server listen start", "port", config.Port, "path", config.Path)
+
+	server := s.server
+	metaroutine.SafeGo(
+		"Metrics serve",
+		func() error {
+			err := server.Serve(listener)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+	)
+	return nil
+}
+
+func (s *Subsystem) Stop() error {
+	if s.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		return metaerror.Wrap(err, "metrics server shutdown failed")
+	}
+	return nil
+}
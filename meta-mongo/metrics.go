@@ -0,0 +1,8 @@
+package metamongo
+
+import metametrics "meta/meta-metrics"
+
+var mongoCommandDuration = metametrics.NewHistogramVec(
+	"mongo_command_duration_seconds", "Mongo command latency.", nil,
+	"command", "status",
+)
@@ -2,14 +2,18 @@ package metamongo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"log/slog"
 	"meta/engine"
 	metaerror "meta/meta-error"
 	metapanic "meta/meta-panic"
+	metatracing "meta/meta-tracing"
 	"meta/subsystem"
 	"sync"
 	"time"
@@ -20,7 +24,8 @@ type Subsystem struct {
 	GetConfig func() *Config
 	client    *mongo.Client
 
-	cmdMap sync.Map // 存储 RequestID -> command 文本
+	cmdMap  sync.Map // 存储 RequestID -> command 文本
+	spanMap sync.Map // 存储 RequestID -> span
 }
 
 func GetSubsystem() *Subsystem {
@@ -30,6 +35,14 @@ func GetSubsystem() *Subsystem {
 	return nil
 }
 
+func (s *Subsystem) endSpan(requestId int64, err error) {
+	value, ok := s.spanMap.LoadAndDelete(requestId)
+	if !ok {
+		return
+	}
+	metatracing.EndSpan(value.(trace.Span), err)
+}
+
 func (s *Subsystem) GetName() string {
 	return "Mongo"
 }
@@ -46,8 +59,17 @@ func (s *Subsystem) Start() error {
 	monitor := &event.CommandMonitor{
 		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
 			s.cmdMap.Store(evt.RequestID, fmt.Sprint(evt.Command))
+			_, span := metatracing.StartSpan(
+				ctx, "mongo "+evt.CommandName, trace.SpanKindClient,
+				attribute.String("db.system", "mongodb"),
+				attribute.String("db.namespace", evt.DatabaseName),
+				attribute.String("db.operation.name", evt.CommandName),
+			)
+			s.spanMap.Store(evt.RequestID, span)
 		},
 		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			mongoCommandDuration.WithLabelValues(evt.CommandName, "ok").Observe(evt.Duration.Seconds())
+			s.endSpan(evt.RequestID, nil)
 			// 从 map 里取出语句
 			val, ok := s.cmdMap.Load(evt.RequestID)
 			if ok {
@@ -80,6 +102,9 @@ func (s *Subsystem) Start() error {
 			}
 		},
 		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			s.cmdMap.Delete(evt.RequestID)
+			mongoCommandDuration.WithLabelValues(evt.CommandName, "error").Observe(evt.Duration.Seconds())
+			s.endSpan(evt.RequestID, errors.New(evt.Failure))
 			slog.Warn(
 				"Mongo command failed",
 				"command_name", evt.CommandName,
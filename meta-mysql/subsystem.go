@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"meta/engine"
 	metaerror "meta/meta-error"
-	metaflag "meta/meta-flag"
 	"meta/meta-sql"
 	"meta/subsystem"
 	"time"
@@ -65,13 +64,10 @@ func (s *Subsystem) Start() error {
 
 		loggerConfig := gormlogger.Config{
 			SlowThreshold:             200 * time.Millisecond,
-			LogLevel:                  gormlogger.Warn,
+			LogLevel:                  gormlogger.Info,
 			IgnoreRecordNotFoundError: false,
 			Colorful:                  true,
 		}
-		if metaflag.IsDebug() {
-			loggerConfig.LogLevel = gormlogger.Info
-		}
 		gormlogger.Default = &metasql.Logger{
 			loggerConfig,
 		}
@@ -80,6 +76,9 @@ func (s *Subsystem) Start() error {
 		if err != nil {
 			return metaerror.Wrap(err, "failed to connect to MySQL")
 		}
+		if err := db.Use(&metasql.TracingPlugin{System: "mysql"}); err != nil {
+			return metaerror.Wrap(err, "failed to register tracing plugin")
+		}
 		sqlDB, err := db.DB()
 		if err != nil {
 			return metaerror.Wrap(err, "failed to get sql.DB")
@@ -7,21 +7,34 @@ import (
 	metaconfig "meta/meta-config"
 	metaerror "meta/meta-error"
 	metaformat "meta/meta-format"
+	metaredact "meta/meta-redact"
 )
 
 func LogError(err error, format ...any) string {
+	message := FormatError(err, format...)
+	slog.Error("Error", "message", message)
+	return message
+}
+
+// FormatError 生成 LogError 记录的内容，不输出日志
+func FormatError(err error, format ...any) string {
 	message := fmt.Sprintf("error [%s] [%s] %s", metaconfig.GetModuleName(), metaconfig.GetNodeName(), host.GetLocalIp())
 	content := metaformat.Format(format...)
 	if content != "" {
 		message = fmt.Sprintf("%s\n%s", message, content)
 	}
 	errStr := fmt.Sprintf("[%s] %+v", metaerror.GetErrorType(err), metaerror.Wrap(err))
-	message = fmt.Sprintf("%s\n%s", message, errStr)
-	slog.Error("Error", "message", message)
-	return message
+	return metaredact.String(fmt.Sprintf("%s\n%s", message, errStr))
 }
 
 func LogPanic(name string, err error, format ...any) string {
+	message := FormatPanic(name, err, format...)
+	slog.Error("Panic", "message", message)
+	return message
+}
+
+// FormatPanic 生成 LogPanic 记录的内容，不输出日志
+func FormatPanic(name string, err error, format ...any) string {
 	message := fmt.Sprintf("panic [%s] [%s] %s", metaconfig.GetModuleName(), metaconfig.GetNodeName(), host.GetLocalIp())
 	message = fmt.Sprintf("%s\n%s", message, name)
 	content := metaformat.Format(format...)
@@ -29,7 +42,5 @@ func LogPanic(name string, err error, format ...any) string {
 		message = fmt.Sprintf("%s\n%s", message, content)
 	}
 	errStr := fmt.Sprintf("[%s] %+v", metaerror.GetErrorType(err), metaerror.Wrap(err))
-	message = fmt.Sprintf("%s\n%s", message, errStr)
-	slog.Error("Panic", "message", message)
-	return message
+	return metaredact.String(fmt.Sprintf("%s\n%s", message, errStr))
 }
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"meta/engine"
 	metaerror "meta/meta-error"
-	metaflag "meta/meta-flag"
 	"meta/meta-sql"
 	"meta/subsystem"
 	"time"
@@ -83,6 +82,9 @@ func (s *Subsystem) Start() error {
 		if err != nil {
 			return metaerror.Wrap(err, fmt.Sprintf("failed to connect to PostgreSQL for key: %s", key))
 		}
+		if err := db.Use(&metasql.TracingPlugin{System: "postgresql"}); err != nil {
+			return metaerror.Wrap(err, "failed to register tracing plugin")
+		}
 
 		sqlDB, err := db.DB()
 		if err != nil {
@@ -124,13 +126,10 @@ func (s *Subsystem) GetClient(key string) *gorm.DB {
 func initGormLogger() gormlogger.Interface {
 	loggerConfig := gormlogger.Config{
 		SlowThreshold:             200 * time.Millisecond,
-		LogLevel:                  gormlogger.Warn,
+		LogLevel:                  gormlogger.Info,
 		IgnoreRecordNotFoundError: false,
 		Colorful:                  true,
 	}
-	if metaflag.IsDebug() {
-		loggerConfig.LogLevel = gormlogger.Info
-	}
 	return &metasql.Logger{
 		Config: loggerConfig,
 	}
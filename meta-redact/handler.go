@@ -0,0 +1,67 @@
+package metaredact
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Handler 对日志消息与属性脱敏，敏感键名的值替换为 Mask
+type Handler struct {
+	slog.Handler
+}
+
+func NewHandler(handler slog.Handler) *Handler {
+	return &Handler{Handler: handler}
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, String(record.Message), record.PC)
+	record.Attrs(
+		func(attr slog.Attr) bool {
+			redacted.AddAttrs(Attr(attr))
+			return true
+		},
+	)
+	return h.Handler.Handle(ctx, redacted)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = Attr(attr)
+	}
+	return &Handler{Handler: h.Handler.WithAttrs(redacted)}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{Handler: h.Handler.WithGroup(name)}
+}
+
+// Attr 返回脱敏后的日志属性
+func Attr(attr slog.Attr) slog.Attr {
+	if IsSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, Mask)
+	}
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, keyString(attr.Key, value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]any, len(group))
+		for i, item := range group {
+			redacted[i] = Attr(item)
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			if message := err.Error(); String(message) != message {
+				return slog.String(attr.Key, String(message))
+			}
+			return attr
+		}
+		return slog.Any(attr.Key, Value(value.Any()))
+	default:
+		return attr
+	}
+}
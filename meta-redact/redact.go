@@ -0,0 +1,219 @@
+package metaredact
+
+import (
+	metaemail "meta/meta-email"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Mask 替换敏感值的文本
+const Mask = "******"
+
+// Pattern 匹配到的文本交给 Replace 处理
+type Pattern struct {
+	Name    string
+	Regexp  *regexp.Regexp
+	Replace func(match string) string
+}
+
+var (
+	mutex    sync.RWMutex
+	keys     = []string{"password", "passwd", "secret", "token", "authorization", "cookie", "apikey", "privatekey"}
+	patterns = []*Pattern{
+		{
+			Name:   "jwt",
+			Regexp: regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+			Replace: func(string) string {
+				return Mask
+			},
+		},
+		{
+			Name:   "url-credential",
+			Regexp: regexp.MustCompile(`://[^/\s:@]+:[^/\s@]+@`),
+			Replace: func(match string) string {
+				return match[:strings.Index(match[3:], ":")+4] + Mask + "@"
+			},
+		},
+		{
+			Name:    "email",
+			Regexp:  regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`),
+			Replace: metaemail.MaskEmail,
+		},
+		{
+			// 任意 11 位数字可能是订单号等，只在有手机号上下文时脱敏
+			Name:    "phone",
+			Regexp:  regexp.MustCompile(`(?i)(?:phone|mobile|\btel|手机|电话|\+86[- ]?)[^0-9]{0,8}1[3-9][0-9]{9}($|[^0-9])`),
+			Replace: maskPhone,
+		},
+	}
+	phoneKeys   = []string{"phone", "mobile"}
+	phoneRegexp = regexp.MustCompile(`(^|[^0-9])1[3-9][0-9]{9}($|[^0-9])`)
+)
+
+// maskPhone 保留手机号前 3 位与后 4 位
+func maskPhone(s string) string {
+	return phoneRegexp.ReplaceAllStringFunc(
+		s, func(match string) string {
+			start := strings.IndexByte(match, '1')
+			return match[:start+3] + "****" + match[start+7:]
+		},
+	)
+}
+
+// isPhoneKey 键名是否表示手机号，如 phone、mobile_no、tel
+func isPhoneKey(key string) bool {
+	key = normalizeKey(key)
+	if key == "tel" {
+		return true
+	}
+	for _, k := range phoneKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyString 对键名对应的文本脱敏，手机号键名的值不需要上下文即按手机号脱敏
+func keyString(key string, s string) string {
+	s = String(s)
+	if isPhoneKey(key) {
+		s = maskPhone(s)
+	}
+	return s
+}
+
+// AddKeys 添加敏感键名，键名忽略大小写、- 与 _，匹配规则见 IsSensitiveKey
+func AddKeys(names ...string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, name := range names {
+		if name = normalizeKey(name); name != "" {
+			keys = append(keys, name)
+		}
+	}
+}
+
+// AddPattern 添加需要脱敏的文本模式，同名的模式会被替换
+func AddPattern(pattern *Pattern) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for i, p := range patterns {
+		if p.Name == pattern.Name {
+			patterns[i] = pattern
+			return
+		}
+	}
+	patterns = append(patterns, pattern)
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.ReplaceAll(key, "-", "")
+	return strings.ReplaceAll(key, "_", "")
+}
+
+// IsSensitiveKey 键名末尾的若干段连起来为敏感词时视为敏感，如 access_token、X-Api-Key、dbPassword
+// 按 _、-、. 与驼峰拆分，token_count、max_tokens 等不视为敏感
+func IsSensitiveKey(key string) bool {
+	segments := splitKey(key)
+	if len(segments) == 0 {
+		return false
+	}
+	mutex.RLock()
+	defer mutex.RUnlock()
+	suffix := ""
+	for i := len(segments) - 1; i >= 0; i-- {
+		suffix = segments[i] + suffix
+		if slices.Contains(keys, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitKey 按 _、-、. 与驼峰拆分键名并转为小写，如 APIKey 拆分为 api 与 key
+func splitKey(key string) []string {
+	var segments []string
+	runes := []rune(key)
+	start := 0
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == '.' {
+			if i > start {
+				segments = append(segments, strings.ToLower(string(runes[start:i])))
+			}
+			start = i + 1
+			continue
+		}
+		if i > start && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+			segments = append(segments, strings.ToLower(string(runes[start:i])))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		segments = append(segments, strings.ToLower(string(runes[start:])))
+	}
+	return segments
+}
+
+// String 对文本中匹配的邮箱、带上下文的手机号、JWT 与 URL 中的密码脱敏
+func String(s string) string {
+	if s == "" {
+		return s
+	}
+	mutex.RLock()
+	defer mutex.RUnlock()
+	for _, pattern := range patterns {
+		s = pattern.Regexp.ReplaceAllStringFunc(s, pattern.Replace)
+	}
+	return s
+}
+
+// Args 对命令参数脱敏，敏感参数如 --password 的值会被替换
+func Args(args []string) []string {
+	result := make([]string, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			result[i] = String(arg)
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !IsSensitiveKey(name) {
+			result[i] = String(arg)
+			continue
+		}
+		if hasValue {
+			result[i] = arg[:strings.Index(arg, "=")+1] + Mask
+			continue
+		}
+		result[i] = arg
+		if i+1 < len(args) {
+			i++
+			result[i] = Mask
+		}
+	}
+	return result
+}
+
+// Query 对敏感参数名的值脱敏，其余值按文本模式脱敏
+func Query(values url.Values) url.Values {
+	result := make(url.Values, len(values))
+	for key, items := range values {
+		redacted := make([]string, len(items))
+		for i, item := range items {
+			if IsSensitiveKey(key) {
+				redacted[i] = Mask
+			} else {
+				redacted[i] = keyString(key, item)
+			}
+		}
+		result[key] = redacted
+	}
+	return result
+}
@@ -0,0 +1,206 @@
+package metaredact
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// maxDepth 防止循环引用
+const maxDepth = 8
+
+// Value 返回脱敏后的副本，结构体转为 map，原值不会被修改
+// 只处理带有 redact 标签的结构体与 map，其余值以及实现了 fmt.Stringer、slog.LogValuer、
+// json.Marshaler 的值原样返回，保持原有的日志格式
+// 字段带有 redact:"true" 标签或字段名、map 键为敏感词时替换为 Mask，字符串按文本模式脱敏
+// 字段带有 redact:"-" 标签时不输出
+func Value(v any) any {
+	if v == nil {
+		return nil
+	}
+	if _, ok := v.(error); ok {
+		return v
+	}
+	rv := reflect.ValueOf(v)
+	if !needRedact(rv.Type()) {
+		return v
+	}
+	return redactValue(rv, 0)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+	logValuerType     = reflect.TypeFor[slog.LogValuer]()
+
+	needRedactTypes sync.Map // reflect.Type -> bool
+)
+
+// isCustomType 自定义了输出格式的类型，如 time.Time、*net.TCPAddr
+func isCustomType(rt reflect.Type) bool {
+	return rt.Implements(jsonMarshalerType) || rt.Implements(textMarshalerType) ||
+		rt.Implements(stringerType) || rt.Implements(logValuerType)
+}
+
+// needRedact 是否为带有 redact 标签的结构体或 map，结果按类型缓存
+func needRedact(rt reflect.Type) bool {
+	if result, ok := needRedactTypes.Load(rt); ok {
+		return result.(bool)
+	}
+	result := checkNeedRedact(rt, make(map[reflect.Type]bool))
+	needRedactTypes.Store(rt, result)
+	return result
+}
+
+func checkNeedRedact(rt reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[rt] || isCustomType(rt) {
+		return false
+	}
+	visited[rt] = true
+	switch rt.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return rt.Elem().Kind() != reflect.Uint8 && checkNeedRedact(rt.Elem(), visited)
+	case reflect.Map:
+		return true
+	case reflect.Struct:
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if _, ok := field.Tag.Lookup("redact"); ok {
+				return true
+			}
+			// 未加标签的结构体中，只有嵌套的带标签结构体需要处理
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct && checkNeedRedact(fieldType, visited) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func redactValue(rv reflect.Value, depth int) any {
+	if !rv.IsValid() {
+		return nil
+	}
+	if depth > maxDepth {
+		return fmt.Sprint(rv.Interface())
+	}
+	// 自定义输出格式的类型保持原样
+	if rv.Kind() != reflect.Interface && rv.CanInterface() && isCustomType(rv.Type()) &&
+		(rv.Kind() != reflect.Pointer || !rv.IsNil()) {
+		return rv.Interface()
+	}
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return redactValue(rv.Elem(), depth+1)
+	case reflect.String:
+		return String(rv.String())
+	case reflect.Struct:
+		return redactStruct(rv, depth)
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+		result := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if IsSensitiveKey(key) {
+				result[key] = Mask
+			} else {
+				result[key] = redactKeyValue(key, iter.Value(), depth+1)
+			}
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface()
+		}
+		result := make([]any, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			result[i] = redactValue(rv.Index(i), depth+1)
+		}
+		return result
+	default:
+		if rv.CanInterface() {
+			return rv.Interface()
+		}
+		return nil
+	}
+}
+
+func redactStruct(rv reflect.Value, depth int) any {
+	rt := rv.Type()
+	result := make(map[string]any, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("redact")
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" {
+			if embedded, ok := redactValue(rv.Field(i), depth+1).(map[string]any); ok {
+				for key, value := range embedded {
+					result[key] = value
+				}
+				continue
+			}
+		}
+		name := getFieldName(field)
+		if tag == "true" || IsSensitiveKey(field.Name) {
+			result[name] = Mask
+			continue
+		}
+		result[name] = redactKeyValue(field.Name, rv.Field(i), depth+1)
+	}
+	return result
+}
+
+// redactKeyValue 字段名或 map 键为手机号键名时，字符串值按手机号脱敏
+func redactKeyValue(key string, rv reflect.Value, depth int) any {
+	if !isPhoneKey(key) {
+		return redactValue(rv, depth)
+	}
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.String {
+		return keyString(key, rv.String())
+	}
+	return redactValue(rv, depth)
+}
+
+// getFieldName 优先使用 json 标签的名称，与日志中 JSON 输出保持一致
+func getFieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
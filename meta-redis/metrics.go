@@ -0,0 +1,51 @@
+package metaredis
+
+import (
+	"context"
+	metametrics "meta/meta-metrics"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var redisCommandDuration = metametrics.NewHistogramVec(
+	"redis_command_duration_seconds", "Redis command latency.", nil,
+	"command", "status",
+)
+
+// metricsHook 记录每条命令与 pipeline 的耗时，pipeline 整体记为一次
+type metricsHook struct {
+}
+
+func (h *metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeCommand(cmd.Name(), time.Since(start), err)
+		return err
+	}
+}
+
+func (h *metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		observeCommand("pipeline", time.Since(start), err)
+		return err
+	}
+}
+
+func observeCommand(command string, elapsed time.Duration, err error) {
+	status := "ok"
+	if ignoreNil(err) != nil {
+		status = "error"
+	}
+	redisCommandDuration.WithLabelValues(command, status).Observe(elapsed.Seconds())
+}
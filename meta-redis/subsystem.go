@@ -36,6 +36,8 @@ func (redisSubsystem *Subsystem) Start() error {
 			Password: config.Password, // 如果没有密码则留空
 		},
 	)
+	redisSubsystem.client.AddHook(&tracingHook{addr: config.Addr})
+	redisSubsystem.client.AddHook(&metricsHook{})
 	ctx := context.Background()
 	if _, err := redisSubsystem.client.Ping(ctx).Result(); err != nil {
 		return err
@@ -0,0 +1,59 @@
+package metaredis
+
+import (
+	"context"
+	"errors"
+	metatracing "meta/meta-tracing"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingHook 为每条 Redis 命令与 pipeline 记录 span
+type tracingHook struct {
+	addr string
+}
+
+func (h *tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := metatracing.StartSpan(
+			ctx, "redis "+cmd.Name(), trace.SpanKindClient,
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation.name", cmd.Name()),
+			attribute.String("server.address", h.addr),
+		)
+		err := next(ctx, cmd)
+		metatracing.EndSpan(span, ignoreNil(err))
+		return err
+	}
+}
+
+func (h *tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := metatracing.StartSpan(
+			ctx, "redis pipeline", trace.SpanKindClient,
+			attribute.String("db.system", "redis"),
+			attribute.Int("db.operation.batch.size", len(cmds)),
+			attribute.String("server.address", h.addr),
+		)
+		err := next(ctx, cmds)
+		metatracing.EndSpan(span, ignoreNil(err))
+		return err
+	}
+}
+
+// ignoreNil 键不存在不视为错误
+func ignoreNil(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package metaresponse
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const localeContextKey = "meta-locale"
+
+// ParseAcceptLanguage 按权重从高到低返回 Accept-Language 中的语言区域
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := strings.TrimSpace(fields[0])
+		if locale == "" || locale == "*" {
+			continue
+		}
+		q := 1.0
+		for _, field := range fields[1:] {
+			field = strings.TrimSpace(field)
+			if strings.HasPrefix(field, "q=") {
+				if value, err := strconv.ParseFloat(field[2:], 64); err == nil {
+					q = value
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		items = append(items, weighted{locale: locale, q: q})
+	}
+	sort.SliceStable(
+		items, func(i, j int) bool {
+			return items[i].q > items[j].q
+		},
+	)
+	locales := make([]string, len(items))
+	for i, item := range items {
+		locales[i] = item.locale
+	}
+	return locales
+}
+
+// SetLocale 覆盖请求的语言区域，如使用用户设置中的语言
+func SetLocale(ctx *gin.Context, locales ...string) {
+	ctx.Set(localeContextKey, locales)
+}
+
+// GetLocales 返回请求的语言区域，未设置时读取 Accept-Language
+func GetLocales(ctx *gin.Context) []string {
+	if value, ok := ctx.Get(localeContextKey); ok {
+		if locales, ok := value.([]string); ok {
+			return locales
+		}
+	}
+	if ctx.Request == nil {
+		return nil
+	}
+	locales := ParseAcceptLanguage(ctx.GetHeader("Accept-Language"))
+	ctx.Set(localeContextKey, locales)
+	return locales
+}
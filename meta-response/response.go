@@ -6,17 +6,28 @@ import (
 	"meta/error-code"
 	metaerror "meta/meta-error"
 	metaformat "meta/meta-format"
+	metatrace "meta/meta-trace"
 	"net/http"
 )
 
 type Response struct {
-	Code int         `json:"code"`
-	Data interface{} `json:"data,omitempty"`
+	Code      int         `json:"code"`
+	Message   string      `json:"message,omitempty"`
+	RequestId string      `json:"request_id,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
 }
 
-func NewResponse[T metaerrorcode.Numeric](ctx *gin.Context, code T, data ...interface{}) {
+// UseHttpStatus 设置后响应使用错误码注册的 HTTP 状态码
+// 默认所有响应都使用 HTTP 200，兼容只读取 code 的客户端
+var UseHttpStatus = false
+
+func newResponse(ctx *gin.Context, code int, data []interface{}) Response {
 	response := Response{
-		Code: int(code),
+		Code:    code,
+		Message: metaerrorcode.GetMessage(code, GetLocales(ctx)...),
+	}
+	if ctx.Request != nil {
+		response.RequestId = metatrace.GetRequestId(ctx.Request.Context())
 	}
 	if data != nil {
 		if len(data) > 1 {
@@ -25,7 +36,24 @@ func NewResponse[T metaerrorcode.Numeric](ctx *gin.Context, code T, data ...inte
 			response.Data = data[0]
 		}
 	}
-	ctx.JSON(http.StatusOK, response)
+	return response
+}
+
+func writeResponse(ctx *gin.Context, response Response) {
+	ctx.JSON(GetHttpStatus(response.Code), response)
+}
+
+// GetHttpStatus 返回错误码响应时使用的 HTTP 状态码，未启用 UseHttpStatus 时为 200
+func GetHttpStatus(code int) int {
+	if !UseHttpStatus {
+		return http.StatusOK
+	}
+	return metaerrorcode.GetHttpStatus(code)
+}
+
+// NewResponse 响应的提示文本取自错误码的注册信息，启用 UseHttpStatus 时 HTTP 状态码也取自注册信息
+func NewResponse[T metaerrorcode.Numeric](ctx *gin.Context, code T, data ...interface{}) {
+	writeResponse(ctx, newResponse(ctx, int(code), data))
 }
 
 func NewResponseError(ctx *gin.Context, err error, data ...interface{}) {
@@ -34,17 +62,12 @@ func NewResponseError(ctx *gin.Context, err error, data ...interface{}) {
 		return
 	}
 	_ = ctx.Error(err)
-	response := Response{
-		Code: metaerror.GetErrorCodeFromError(err),
-	}
-	if data != nil {
-		if len(data) > 1 {
-			response.Data = data
-		} else if len(data) > 0 {
-			response.Data = data[0]
-		}
+	// 只返回错误码对应的提示或显式设置的用户提示，错误详情只记录在服务端
+	response := newResponse(ctx, metaerror.GetErrorCodeFromError(err), data)
+	if message := metaerror.GetUserMessage(err); message != "" {
+		response.Message = message
 	}
-	ctx.JSON(http.StatusOK, response)
+	writeResponse(ctx, response)
 }
 
 func NewResponseWrapCode[T metaerrorcode.Numeric](
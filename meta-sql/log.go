@@ -5,16 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	metalog "meta/meta-log"
 	"time"
 
 	gormlogger "gorm.io/gorm/logger"
 	"gorm.io/gorm/utils"
 )
 
+// Logger gorm 日志，SQL 语句以 Debug 等级输出，由 metalog 中 gorm 组件的等级控制，可运行时调整
 type Logger struct {
 	gormlogger.Config
 }
 
+var logger = metalog.GetModuleLogger(metalog.LoggerGorm)
+
 // LogMode log mode
 func (l *Logger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
 	newLogger := *l
@@ -25,21 +29,21 @@ func (l *Logger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
 // Info print info
 func (l *Logger) Info(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= gormlogger.Info {
-		slog.InfoContext(ctx, "gorm", "msg", fmt.Sprintf(msg, data...), "stack", utils.FileWithLineNum())
+		logger.InfoContext(ctx, "gorm", "msg", fmt.Sprintf(msg, data...), "stack", utils.FileWithLineNum())
 	}
 }
 
 // Warn print warn messages
 func (l *Logger) Warn(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= gormlogger.Warn {
-		slog.WarnContext(ctx, "gorm", "msg", fmt.Sprintf(msg, data...), "stack", utils.FileWithLineNum())
+		logger.WarnContext(ctx, "gorm", "msg", fmt.Sprintf(msg, data...), "stack", utils.FileWithLineNum())
 	}
 }
 
 // Error print error messages
 func (l *Logger) Error(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= gormlogger.Error {
-		slog.ErrorContext(ctx, "gorm", "msg", fmt.Sprintf(msg, data...), "stack", utils.FileWithLineNum())
+		logger.ErrorContext(ctx, "gorm", "msg", fmt.Sprintf(msg, data...), "stack", utils.FileWithLineNum())
 	}
 }
 
@@ -47,11 +51,13 @@ func (l *Logger) Error(ctx context.Context, msg string, data ...interface{}) {
 //
 //nolint:cyclop
 func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	observeQuery(fc, elapsed, err)
+
 	if l.LogLevel <= gormlogger.Silent {
 		return
 	}
 
-	elapsed := time.Since(begin)
 	switch {
 	case err != nil && l.LogLevel >= gormlogger.Error && (!errors.Is(
 		err,
@@ -59,7 +65,7 @@ func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string,
 	) || !l.IgnoreRecordNotFoundError):
 		sql, rows := fc()
 		if rows == -1 {
-			slog.ErrorContext(
+			logger.ErrorContext(
 				ctx, "gorm",
 				"err",
 				err,
@@ -73,7 +79,7 @@ func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string,
 				utils.FileWithLineNum(),
 			)
 		} else {
-			slog.ErrorContext(
+			logger.ErrorContext(
 				ctx, "gorm",
 				"err",
 				err,
@@ -91,7 +97,7 @@ func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string,
 		sql, rows := fc()
 		slowLog := fmt.Sprintf("SLOW SQL >= %v", l.SlowThreshold)
 		if rows == -1 {
-			slog.WarnContext(
+			logger.WarnContext(
 				ctx, "gorm",
 				"slowLog",
 				slowLog,
@@ -105,7 +111,7 @@ func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string,
 				utils.FileWithLineNum(),
 			)
 		} else {
-			slog.WarnContext(
+			logger.WarnContext(
 				ctx, "gorm",
 				"slowLog",
 				slowLog,
@@ -119,10 +125,10 @@ func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string,
 				utils.FileWithLineNum(),
 			)
 		}
-	case l.LogLevel == gormlogger.Info:
+	case l.LogLevel == gormlogger.Info && logger.Enabled(ctx, slog.LevelDebug):
 		sql, rows := fc()
 		if rows == -1 {
-			slog.InfoContext(
+			logger.DebugContext(
 				ctx, "gorm",
 				"elapsed",
 				float64(elapsed.Nanoseconds())/1e6,
@@ -134,7 +140,7 @@ func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string,
 				utils.FileWithLineNum(),
 			)
 		} else {
-			slog.InfoContext(
+			logger.DebugContext(
 				ctx, "gorm",
 				"elapsed",
 				float64(elapsed.Nanoseconds())/1e6,
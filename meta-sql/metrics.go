@@ -0,0 +1,42 @@
+package metasql
+
+import (
+	"errors"
+	metametrics "meta/meta-metrics"
+	"strings"
+	"time"
+
+	gormlogger "gorm.io/gorm/logger"
+)
+
+var sqlQueryDuration = metametrics.NewHistogramVec(
+	"sql_query_duration_seconds", "SQL query latency.", nil,
+	"operation", "status",
+)
+
+var sqlOperations = map[string]struct{}{
+	"SELECT": {}, "INSERT": {}, "UPDATE": {}, "DELETE": {}, "CREATE": {}, "ALTER": {}, "DROP": {},
+	"BEGIN": {}, "COMMIT": {}, "ROLLBACK": {}, "SAVEPOINT": {}, "WITH": {}, "SHOW": {},
+}
+
+func observeQuery(fc func() (string, int64), elapsed time.Duration, err error) {
+	status := "ok"
+	if err != nil && !errors.Is(err, gormlogger.ErrRecordNotFound) {
+		status = "error"
+	}
+	sql, _ := fc()
+	sqlQueryDuration.WithLabelValues(getSqlOperation(sql), status).Observe(elapsed.Seconds())
+}
+
+// getSqlOperation 取语句的第一个关键字，限定取值范围避免标签过多
+func getSqlOperation(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if i := strings.IndexAny(sql, " \t\n("); i >= 0 {
+		sql = sql[:i]
+	}
+	operation := strings.ToUpper(sql)
+	if _, ok := sqlOperations[operation]; ok {
+		return operation
+	}
+	return "OTHER"
+}
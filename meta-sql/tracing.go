@@ -0,0 +1,76 @@
+package metasql
+
+import (
+	metatracing "meta/meta-tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const tracingSpanKey = "meta:tracing_span"
+
+// TracingPlugin 为每条 gorm 语句记录 span，通过 db.Use 注册
+type TracingPlugin struct {
+	System string // 数据库类型，如 mysql、postgresql
+}
+
+func (p *TracingPlugin) Name() string {
+	return "meta:tracing"
+}
+
+func (p *TracingPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	processors := []struct {
+		name   string
+		before func(name string, fn func(*gorm.DB)) error
+		after  func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callback.Create().Before("gorm:create").Register, callback.Create().After("gorm:create").Register},
+		{"query", callback.Query().Before("gorm:query").Register, callback.Query().After("gorm:query").Register},
+		{"update", callback.Update().Before("gorm:update").Register, callback.Update().After("gorm:update").Register},
+		{"delete", callback.Delete().Before("gorm:delete").Register, callback.Delete().After("gorm:delete").Register},
+		{"row", callback.Row().Before("gorm:row").Register, callback.Row().After("gorm:row").Register},
+		{"raw", callback.Raw().Before("gorm:raw").Register, callback.Raw().After("gorm:raw").Register},
+	}
+	for _, processor := range processors {
+		if err := processor.before("meta:tracing_before_"+processor.name, p.before); err != nil {
+			return err
+		}
+		if err := processor.after("meta:tracing_after_"+processor.name, p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *TracingPlugin) before(db *gorm.DB) {
+	ctx, span := metatracing.StartSpan(
+		db.Statement.Context, "gorm "+db.Statement.Table, trace.SpanKindClient,
+		attribute.String("db.system", p.System),
+		attribute.String("db.collection.name", db.Statement.Table),
+	)
+	db.Statement.Context = ctx
+	db.InstanceSet(tracingSpanKey, span)
+}
+
+func (p *TracingPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(tracingSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("db.query.text", db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	err := db.Error
+	if err == gormlogger.ErrRecordNotFound {
+		err = nil
+	}
+	metatracing.EndSpan(span, err)
+}
@@ -0,0 +1,64 @@
+package metatrace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const (
+	HeaderRequestId   = "X-Request-ID"
+	HeaderTraceParent = "traceparent"
+)
+
+type requestIdKey struct{}
+type traceParentKey struct{}
+type remoteTraceParentKey struct{}
+
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+func GetRequestId(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
+
+func WithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return context.WithValue(ctx, traceParentKey{}, traceParent)
+}
+
+func GetTraceParent(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceParent, _ := ctx.Value(traceParentKey{}).(string)
+	return traceParent
+}
+
+// WithRemoteTraceParent 记录入站请求中原始的 traceparent，开启 span 时以其作为远程父节点
+func WithRemoteTraceParent(ctx context.Context, traceParent string) context.Context {
+	return context.WithValue(ctx, remoteTraceParentKey{}, traceParent)
+}
+
+func GetRemoteTraceParent(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceParent, _ := ctx.Value(remoteTraceParentKey{}).(string)
+	return traceParent
+}
+
+// NewRequestId 生成 32 位十六进制的请求ID
+func NewRequestId() string {
+	return randomHex(16)
+}
+
+func randomHex(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
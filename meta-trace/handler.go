@@ -0,0 +1,33 @@
+package metatrace
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Handler 为带有请求ID与 traceparent 的 context 的日志自动添加 request_id 与 trace_id
+type Handler struct {
+	slog.Handler
+}
+
+func NewHandler(handler slog.Handler) *Handler {
+	return &Handler{Handler: handler}
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	if requestId := GetRequestId(ctx); requestId != "" {
+		record.AddAttrs(slog.String("request_id", requestId))
+	}
+	if traceId := GetTraceId(ctx); traceId != "" {
+		record.AddAttrs(slog.String("trace_id", traceId))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{Handler: h.Handler.WithGroup(name)}
+}
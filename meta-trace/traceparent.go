@@ -0,0 +1,126 @@
+package metatrace
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	traceParentVersion = "00"
+	traceFlagSampled   = "01"
+)
+
+// TraceParent W3C traceparent，格式为 {version}-{trace-id}-{parent-id}-{flags}
+type TraceParent struct {
+	TraceId  string // 32 位十六进制
+	ParentId string // 16 位十六进制，当前调用方的 span id
+	Flags    string // 2 位十六进制
+}
+
+// ParseTraceParent 解析失败时返回 false
+func ParseTraceParent(value string) (TraceParent, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return TraceParent{}, false
+	}
+	// 版本 00 必须恰好四段，更高版本允许追加字段
+	if parts[0] == traceParentVersion && len(parts) != 4 {
+		return TraceParent{}, false
+	}
+	traceParent := TraceParent{
+		TraceId:  parts[1],
+		ParentId: parts[2],
+		Flags:    parts[3],
+	}
+	if !isHex(parts[0]) ||
+		!isValidId(traceParent.TraceId, 32) ||
+		!isValidId(traceParent.ParentId, 16) ||
+		len(traceParent.Flags) != 2 || !isHex(traceParent.Flags) {
+		return TraceParent{}, false
+	}
+	return traceParent, true
+}
+
+// NewTraceParent 开启一条新的链路
+func NewTraceParent() TraceParent {
+	return TraceParent{
+		TraceId:  randomHex(16),
+		ParentId: NewSpanId(),
+		Flags:    traceFlagSampled,
+	}
+}
+
+// NewSpanId 生成 16 位十六进制的 span id
+func NewSpanId() string {
+	return randomHex(8)
+}
+
+// Child 保留 trace id，生成新的 span id
+func (t TraceParent) Child() TraceParent {
+	return TraceParent{
+		TraceId:  t.TraceId,
+		ParentId: NewSpanId(),
+		Flags:    t.Flags,
+	}
+}
+
+func (t TraceParent) String() string {
+	return traceParentVersion + "-" + t.TraceId + "-" + t.ParentId + "-" + t.Flags
+}
+
+// GetTraceId 返回 context 中 traceparent 的 trace id
+func GetTraceId(ctx context.Context) string {
+	traceParent, ok := ParseTraceParent(GetTraceParent(ctx))
+	if !ok {
+		return ""
+	}
+	return traceParent.TraceId
+}
+
+// Inject 将请求ID与 traceparent 写入请求头、消息头等载体
+func Inject(ctx context.Context, set func(key string, value string)) {
+	if requestId := GetRequestId(ctx); requestId != "" {
+		set(HeaderRequestId, requestId)
+	}
+	if traceParent := GetTraceParent(ctx); traceParent != "" {
+		set(HeaderTraceParent, traceParent)
+	}
+}
+
+// Extract 从载体中读取请求ID与 traceparent，非法的 traceparent 会被忽略
+func Extract(ctx context.Context, get func(key string) string) context.Context {
+	if requestId := get(HeaderRequestId); IsValidRequestId(requestId) {
+		ctx = WithRequestId(ctx, requestId)
+	}
+	if traceParent, ok := ParseTraceParent(get(HeaderTraceParent)); ok {
+		ctx = WithTraceParent(ctx, traceParent.String())
+	}
+	return ctx
+}
+
+// IsValidRequestId 限制长度与字符，避免外部传入的值污染日志
+func IsValidRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > 128 {
+		return false
+	}
+	for i := 0; i < len(requestId); i++ {
+		if requestId[i] < 0x21 || requestId[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func isValidId(id string, size int) bool {
+	return len(id) == size && isHex(id) && strings.Trim(id, "0") != ""
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
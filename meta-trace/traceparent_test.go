@@ -0,0 +1,53 @@
+package metatrace
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		value string
+		ok    bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if _, ok := ParseTraceParent(test.value); ok != test.ok {
+			t.Errorf("ParseTraceParent(%q) = %v, want %v", test.value, ok, test.ok)
+		}
+	}
+
+	traceParent := NewTraceParent()
+	parsed, ok := ParseTraceParent(traceParent.String())
+	if !ok || parsed != traceParent {
+		t.Errorf("new traceparent round trip failed: %s", traceParent)
+	}
+	child := traceParent.Child()
+	if child.TraceId != traceParent.TraceId || child.ParentId == traceParent.ParentId {
+		t.Errorf("child should keep trace id with new span id: %s -> %s", traceParent, child)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewTextHandler(&buffer, nil)))
+	ctx := WithRequestId(context.Background(), "req-1")
+	ctx = WithTraceParent(ctx, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	logger.With("module", "test").InfoContext(ctx, "hello")
+	output := buffer.String()
+	if !strings.Contains(output, "request_id=req-1") || !strings.Contains(output, "trace_id=4bf92f3577b34da6a3ce929d0e0e4736") {
+		t.Errorf("unexpected log output: %s", output)
+	}
+}
@@ -0,0 +1,10 @@
+package metatracing
+
+type Config struct {
+	Exporter    string  `yaml:"exporter"`     // 导出方式：stdout、file、otlp 或通过 RegisterExporter 注册的名称，为空不导出
+	FilePath    string  `yaml:"file-path"`    // file 导出的文件路径，默认为 logs/trace.jsonl
+	Endpoint    string  `yaml:"endpoint"`     // otlp 采集器地址，默认为 localhost:4318
+	Insecure    bool    `yaml:"insecure"`     // otlp 使用 http 而非 https
+	SampleRatio float64 `yaml:"sample-ratio"` // 无上游时的采样比例，默认为 1
+	ServiceName string  `yaml:"service-name"` // 服务名，默认为模块名
+}
@@ -0,0 +1,85 @@
+package metatracing
+
+import (
+	"context"
+	metaerror "meta/meta-error"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ExporterFactory 根据配置创建导出器
+type ExporterFactory func(config *Config) (sdktrace.SpanExporter, error)
+
+var (
+	exporterMutex     sync.RWMutex
+	exporterFactories = map[string]ExporterFactory{
+		"stdout": newStdoutExporter,
+		"file":   newFileExporter,
+		"otlp":   newOtlpExporter,
+	}
+)
+
+// RegisterExporter 注册自定义导出器，需在子系统启动前调用
+func RegisterExporter(name string, factory ExporterFactory) {
+	exporterMutex.Lock()
+	defer exporterMutex.Unlock()
+	exporterFactories[name] = factory
+}
+
+func getExporterFactory(name string) (ExporterFactory, bool) {
+	exporterMutex.RLock()
+	defer exporterMutex.RUnlock()
+	factory, ok := exporterFactories[name]
+	return factory, ok
+}
+
+func newStdoutExporter(_ *Config) (sdktrace.SpanExporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+}
+
+// newFileExporter 每行一个 JSON 格式的 span
+func newFileExporter(config *Config) (sdktrace.SpanExporter, error) {
+	path := config.FilePath
+	if path == "" {
+		path = filepath.Join("logs", "trace.jsonl")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, metaerror.Wrap(err, "create trace file directory failed, path:%s", path)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "open trace file failed, path:%s", path)
+	}
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &fileExporter{SpanExporter: exporter, file: file}, nil
+}
+
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	return metaerror.Join(e.SpanExporter.Shutdown(ctx), e.file.Close())
+}
+
+func newOtlpExporter(config *Config) (sdktrace.SpanExporter, error) {
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "localhost:4318"
+	}
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if config.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	return otlptracehttp.New(context.Background(), options...)
+}
@@ -0,0 +1,92 @@
+package metatracing
+
+import (
+	"context"
+	metatrace "meta/meta-trace"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "meta"
+
+// StartSpan 开启 span，并将其写回 metatrace 的 traceparent，使日志与出站请求使用同一链路
+// context 中没有 span 时，以入站的原始 traceparent 或 metatrace 中的 traceparent 作为远程父节点
+func StartSpan(
+	ctx context.Context,
+	name string,
+	kind trace.SpanKind,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	parent := trace.SpanContextFromContext(ctx)
+	if !parent.IsValid() {
+		traceParent := metatrace.GetRemoteTraceParent(ctx)
+		if traceParent == "" {
+			traceParent = metatrace.GetTraceParent(ctx)
+		}
+		if remote, ok := parseSpanContext(traceParent); ok {
+			ctx = trace.ContextWithRemoteSpanContext(ctx, remote)
+			parent = remote
+		}
+	}
+	ctx, span := otel.Tracer(instrumentationName).Start(
+		ctx, name,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(attrs...),
+	)
+	spanContext := span.SpanContext()
+	if spanContext.IsValid() && spanContext.SpanID() != parent.SpanID() {
+		ctx = metatrace.WithTraceParent(ctx, formatTraceParent(spanContext))
+	}
+	return ctx, span
+}
+
+// EndSpan err 不为空时记录错误并标记 span 失败
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func parseSpanContext(value string) (trace.SpanContext, bool) {
+	traceParent, ok := metatrace.ParseTraceParent(value)
+	if !ok {
+		return trace.SpanContext{}, false
+	}
+	traceId, err := trace.TraceIDFromHex(traceParent.TraceId)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	spanId, err := trace.SpanIDFromHex(traceParent.ParentId)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	var flags trace.TraceFlags
+	if traceParent.Flags[1]&1 == 1 {
+		flags = trace.FlagsSampled
+	}
+	return trace.NewSpanContext(
+		trace.SpanContextConfig{
+			TraceID:    traceId,
+			SpanID:     spanId,
+			TraceFlags: flags,
+			Remote:     true,
+		},
+	), true
+}
+
+func formatTraceParent(spanContext trace.SpanContext) string {
+	flags := "00"
+	if spanContext.IsSampled() {
+		flags = "01"
+	}
+	return metatrace.TraceParent{
+		TraceId:  spanContext.TraceID().String(),
+		ParentId: spanContext.SpanID().String(),
+		Flags:    flags,
+	}.String()
+}
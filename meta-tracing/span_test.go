@@ -0,0 +1,43 @@
+package metatracing
+
+import (
+	"context"
+	metatrace "meta/meta-trace"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStartSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	incoming := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := metatrace.WithTraceParent(context.Background(), incoming)
+	ctx, parent := StartSpan(ctx, "parent", trace.SpanKindServer)
+	_, child := StartSpan(ctx, "child", trace.SpanKindClient)
+	EndSpan(child, nil)
+	EndSpan(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expect 2 spans, got %d", len(spans))
+	}
+	childSpan, parentSpan := spans[0], spans[1]
+	if parentSpan.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" ||
+		parentSpan.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("parent span should continue incoming traceparent: %v", parentSpan.Parent())
+	}
+	if childSpan.Parent().SpanID() != parentSpan.SpanContext().SpanID() {
+		t.Error("child span should be a child of parent span")
+	}
+	want := formatTraceParent(parentSpan.SpanContext())
+	if got := metatrace.GetTraceParent(ctx); got != want {
+		t.Errorf("traceparent in context = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+package metatracing
+
+import (
+	"context"
+	"log/slog"
+	"meta/engine"
+	metaconfig "meta/meta-config"
+	metaerror "meta/meta-error"
+	"meta/subsystem"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+type Subsystem struct {
+	subsystem.Subsystem
+	GetConfig func() *Config
+
+	provider *sdktrace.TracerProvider
+}
+
+func GetSubsystem() *Subsystem {
+	if thisSubsystem := engine.GetSubsystem[*Subsystem](); thisSubsystem != nil {
+		return thisSubsystem.(*Subsystem)
+	}
+	return nil
+}
+
+func (s *Subsystem) GetName() string {
+	return "Tracing"
+}
+
+// Start 需要注册在其他子系统之前，使其他子系统启动时已能记录 span
+func (s *Subsystem) Start() error {
+	config := &Config{}
+	if s.GetConfig != nil {
+		if c := s.GetConfig(); c != nil {
+			config = c
+		}
+	}
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	if config.Exporter == "" {
+		return nil
+	}
+	factory, ok := getExporterFactory(config.Exporter)
+	if !ok {
+		return metaerror.New("tracing exporter not found: %s", config.Exporter)
+	}
+	exporter, err := factory(config)
+	if err != nil {
+		return metaerror.Wrap(err, "create tracing exporter failed: %s", config.Exporter)
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = metaconfig.GetModuleName()
+	}
+	if config.SampleRatio <= 0 {
+		config.SampleRatio = 1
+	}
+	s.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(
+			resource.NewSchemaless(
+				semconv.ServiceName(config.ServiceName),
+				semconv.ServiceInstanceID(metaconfig.GetNodeName()),
+			),
+		),
+	)
+	otel.SetTracerProvider(s.provider)
+	slog.Info("Tracing start", "exporter", config.Exporter, "service", config.ServiceName)
+	return nil
+}
+
+// Stop 导出剩余的 span，需注册在其他子系统之前以便最后停止
+func (s *Subsystem) Stop() error {
+	if s.provider == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.provider.Shutdown(ctx); err != nil {
+		return metaerror.Wrap(err, "tracing shutdown failed")
+	}
+	return nil
+}
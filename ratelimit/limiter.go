@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmTokenBucket   Algorithm = "token-bucket"
+	AlgorithmSlidingWindow Algorithm = "sliding-window"
+)
+
+// Rule 每个 Window 内最多允许 Limit 次请求
+// 令牌桶算法下 Limit 同时作为桶容量，允许短时突发
+type Rule struct {
+	Algorithm Algorithm     `yaml:"algorithm"` // 限流算法，默认为令牌桶
+	Limit     int           `yaml:"limit"`     // 窗口内允许的请求数
+	Window    time.Duration `yaml:"window"`    // 窗口长度
+}
+
+func (r *Rule) setDefault() {
+	if r.Algorithm == "" {
+		r.Algorithm = AlgorithmTokenBucket
+	}
+	if r.Limit <= 0 {
+		r.Limit = 1
+	}
+	if r.Window <= 0 {
+		r.Window = time.Second
+	}
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // 额度完全恢复所需时间
+	RetryAfter time.Duration // 被拒绝时需要等待的时间
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string) (*Result, error)
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryLimiter 单节点内存限流，按 key 分别计数
+type MemoryLimiter struct {
+	rule Rule
+	now  func() time.Time
+
+	mutex     sync.Mutex
+	buckets   map[string]*TokenBucket
+	windows   map[string][]time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryLimiter(rule Rule) *MemoryLimiter {
+	rule.setDefault()
+	return &MemoryLimiter{
+		rule:    rule,
+		now:     time.Now,
+		buckets: make(map[string]*TokenBucket),
+		windows: make(map[string][]time.Time),
+	}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string) (*Result, error) {
+	now := l.now()
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.sweep(now)
+	if l.rule.Algorithm == AlgorithmSlidingWindow {
+		return l.allowSlidingWindow(now, key), nil
+	}
+	return l.allowTokenBucket(now, key), nil
+}
+
+func (l *MemoryLimiter) allowTokenBucket(now time.Time, key string) *Result {
+	bucket, ok := l.buckets[key]
+	if !ok {
+		rate := float64(l.rule.Limit) / l.rule.Window.Seconds()
+		bucket = NewTokenBucket(rate, l.rule.Limit)
+		bucket.last = now
+		l.buckets[key] = bucket
+	}
+	allowed, wait := bucket.AllowAt(now, 1)
+	remaining := bucket.Remaining(now)
+	return &Result{
+		Allowed:    allowed,
+		Limit:      l.rule.Limit,
+		Remaining:  remaining,
+		Reset:      time.Duration(float64(l.rule.Limit-remaining) / bucket.rate * float64(time.Second)),
+		RetryAfter: wait,
+	}
+}
+
+func (l *MemoryLimiter) allowSlidingWindow(now time.Time, key string) *Result {
+	window := l.windows[key]
+	start := now.Add(-l.rule.Window)
+	i := 0
+	for i < len(window) && !window[i].After(start) {
+		i++
+	}
+	window = window[i:]
+	result := &Result{
+		Limit: l.rule.Limit,
+	}
+	if len(window) < l.rule.Limit {
+		window = append(window, now)
+		result.Allowed = true
+	} else {
+		result.RetryAfter = window[0].Sub(start)
+	}
+	l.windows[key] = window
+	result.Remaining = l.rule.Limit - len(window)
+	result.Reset = window[len(window)-1].Sub(start)
+	return result
+}
+
+// sweep 每个窗口清理一次已经完全恢复的 key
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.rule.Window {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		if bucket.Remaining(now) >= l.rule.Limit {
+			delete(l.buckets, key)
+		}
+	}
+	start := now.Add(-l.rule.Window)
+	for key, window := range l.windows {
+		if len(window) == 0 || !window[len(window)-1].After(start) {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryLimiterTokenBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewMemoryLimiter(Rule{Limit: 2, Window: time.Second})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		result, _ := limiter.Allow(context.Background(), "a")
+		if !result.Allowed {
+			t.Fatalf("request %d should be allowed", i)
+		}
+	}
+	result, _ := limiter.Allow(context.Background(), "a")
+	if result.Allowed || result.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("third request should wait 500ms: %+v", result)
+	}
+	if result, _ := limiter.Allow(context.Background(), "b"); !result.Allowed {
+		t.Fatal("other key should be allowed")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if result, _ := limiter.Allow(context.Background(), "a"); !result.Allowed {
+		t.Fatal("request should be allowed after refill")
+	}
+}
+
+func TestMemoryLimiterSlidingWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewMemoryLimiter(Rule{Algorithm: AlgorithmSlidingWindow, Limit: 2, Window: time.Second})
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow(context.Background(), "a")
+	now = now.Add(400 * time.Millisecond)
+	result, _ := limiter.Allow(context.Background(), "a")
+	if !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("second request should be allowed: %+v", result)
+	}
+	result, _ = limiter.Allow(context.Background(), "a")
+	if result.Allowed || result.RetryAfter != 600*time.Millisecond {
+		t.Fatalf("third request should wait 600ms: %+v", result)
+	}
+
+	now = now.Add(600 * time.Millisecond)
+	result, _ = limiter.Allow(context.Background(), "a")
+	if !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("request should be allowed after first expires: %+v", result)
+	}
+}
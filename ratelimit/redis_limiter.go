@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	metaerror "meta/meta-error"
+	metaredis "meta/meta-redis"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 使用 Redis 服务器时间，避免各节点时钟不一致
+// 返回 是否允许、剩余数量、完全恢复毫秒数、需等待毫秒数
+var tokenBucketScript = redis.NewScript(
+	`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local rate = limit / window
+local tokens = tonumber(redis.call('HGET', KEYS[1], 'tokens'))
+local last = tonumber(redis.call('HGET', KEYS[1], 'last'))
+if tokens == nil then
+	tokens = limit
+	last = now
+end
+if now > last then
+	tokens = math.min(limit, tokens + (now - last) * rate)
+	last = now
+end
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', last)
+redis.call('PEXPIRE', KEYS[1], window)
+local reset = math.ceil((limit - tokens) / rate)
+return {allowed, math.floor(tokens), reset, wait}
+`,
+)
+
+var slidingWindowScript = redis.NewScript(
+	`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local start = now - window
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', start)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+local wait = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, time[1] .. time[2] .. ':' .. ARGV[3])
+	count = count + 1
+	allowed = 1
+else
+	local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+	wait = tonumber(oldest[2]) - start
+end
+redis.call('PEXPIRE', KEYS[1], window)
+local newest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+local reset = 0
+if newest[2] then
+	reset = tonumber(newest[2]) - start
+end
+return {allowed, limit - count, reset, wait}
+`,
+)
+
+// RedisLimiter 基于 Redis 的分布式限流，多节点共享计数
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+	rule   Rule
+}
+
+// NewRedisLimiter client 为空时使用 metaredis 子系统的连接
+func NewRedisLimiter(client *redis.Client, prefix string, rule Rule) (*RedisLimiter, error) {
+	rule.setDefault()
+	if client == nil {
+		redisSubsystem := metaredis.GetSubsystem()
+		if redisSubsystem == nil || redisSubsystem.GetClient() == nil {
+			return nil, metaerror.New("rate limiter redis client is nil and redis subsystem not found")
+		}
+		client = redisSubsystem.GetClient()
+	}
+	if prefix == "" {
+		prefix = "meta:ratelimit"
+	}
+	return &RedisLimiter{
+		client: client,
+		prefix: prefix,
+		rule:   rule,
+	}, nil
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (*Result, error) {
+	script := tokenBucketScript
+	if l.rule.Algorithm == AlgorithmSlidingWindow {
+		script = slidingWindowScript
+	}
+	args := []any{l.rule.Limit, l.rule.Window.Milliseconds()}
+	if l.rule.Algorithm == AlgorithmSlidingWindow {
+		args = append(args, strconv.FormatInt(time.Now().UnixNano(), 36))
+	}
+	values, err := script.Run(ctx, l.client, []string{l.prefix + ":" + key}, args...).Int64Slice()
+	if err != nil {
+		return nil, metaerror.Wrap(err, "rate limit failed, key:%s", key)
+	}
+	if len(values) != 4 {
+		return nil, metaerror.New("rate limit script result invalid, key:%s", key)
+	}
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      l.rule.Limit,
+		Remaining:  int(values[1]),
+		Reset:      time.Duration(values[2]) * time.Millisecond,
+		RetryAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedisLimiter(t *testing.T, rule Rule) (*RedisLimiter, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(
+		func() {
+			_ = client.Close()
+		},
+	)
+	limiter, err := NewRedisLimiter(client, "", rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return limiter, server
+}
+
+func TestNewRedisLimiterWithoutRedis(t *testing.T) {
+	if _, err := NewRedisLimiter(nil, "", Rule{Limit: 1, Window: time.Second}); err == nil {
+		t.Fatal("missing redis should return error")
+	}
+}
+
+func TestRedisLimiterTokenBucket(t *testing.T) {
+	limiter, server := newTestRedisLimiter(t, Rule{Limit: 2, Window: time.Second})
+	now := time.Unix(1000, 0)
+	server.SetTime(now)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Allow(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Allowed || result.Remaining != 1-i {
+			t.Fatalf("request %d should be allowed: %+v", i, result)
+		}
+	}
+	result, err := limiter.Allow(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Allowed || result.RetryAfter != 500*time.Millisecond || result.Reset != time.Second {
+		t.Fatalf("third request should wait 500ms: %+v", result)
+	}
+	if result, _ := limiter.Allow(ctx, "b"); !result.Allowed {
+		t.Fatal("other key should be allowed")
+	}
+	if ttl := server.TTL("meta:ratelimit:a"); ttl <= 0 || ttl > time.Second {
+		t.Errorf("key ttl = %v", ttl)
+	}
+
+	server.SetTime(now.Add(500 * time.Millisecond))
+	if result, _ := limiter.Allow(ctx, "a"); !result.Allowed {
+		t.Fatal("request should be allowed after refill")
+	}
+}
+
+func TestRedisLimiterSlidingWindow(t *testing.T) {
+	limiter, server := newTestRedisLimiter(t, Rule{Algorithm: AlgorithmSlidingWindow, Limit: 2, Window: time.Second})
+	now := time.Unix(1000, 0)
+	server.SetTime(now)
+	ctx := context.Background()
+
+	if result, _ := limiter.Allow(ctx, "a"); !result.Allowed {
+		t.Fatal("first request should be allowed")
+	}
+	server.SetTime(now.Add(400 * time.Millisecond))
+	if result, _ := limiter.Allow(ctx, "a"); !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("second request should be allowed: %+v", result)
+	}
+	result, _ := limiter.Allow(ctx, "a")
+	if result.Allowed || result.RetryAfter != 600*time.Millisecond {
+		t.Fatalf("third request should wait 600ms: %+v", result)
+	}
+
+	// 最早的请求移出窗口后放行
+	server.SetTime(now.Add(1001 * time.Millisecond))
+	if result, _ := limiter.Allow(ctx, "a"); !result.Allowed {
+		t.Fatalf("request should be allowed after window: %+v", result)
+	}
+}
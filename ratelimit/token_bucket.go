@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket 令牌桶，按 rate 每秒补充令牌，最多累积 burst 个
+type TokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *TokenBucket) Allow() bool {
+	ok, _ := b.AllowAt(time.Now(), 1)
+	return ok
+}
+
+// AllowAt 尝试在 now 时刻取出 n 个令牌
+// 返回是否成功，失败时同时返回需要等待的时间
+func (b *TokenBucket) AllowAt(now time.Time, n int) (bool, time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill(now)
+	need := float64(n)
+	if b.tokens >= need {
+		b.tokens -= need
+		return true, 0
+	}
+	if b.rate <= 0 {
+		return false, time.Duration(1<<63 - 1)
+	}
+	wait := time.Duration((need - b.tokens) / b.rate * float64(time.Second))
+	return false, wait
+}
+
+// Remaining 返回 now 时刻剩余的令牌数
+func (b *TokenBucket) Remaining(now time.Time) int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refill(now)
+	return int(b.tokens)
+}
+
+func (b *TokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurst(t *testing.T) {
+	bucket := NewTokenBucket(1, 3)
+	now := bucket.last
+	for i := 0; i < 3; i++ {
+		if ok, _ := bucket.AllowAt(now, 1); !ok {
+			t.Fatalf("Expected token %d to be allowed", i)
+		}
+	}
+	ok, wait := bucket.AllowAt(now, 1)
+	if ok {
+		t.Fatalf("Expected bucket to be empty")
+	}
+	if wait != time.Second {
+		t.Errorf("Expected wait %v, got %v", time.Second, wait)
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	bucket := NewTokenBucket(10, 1)
+	now := bucket.last
+	if ok, _ := bucket.AllowAt(now, 1); !ok {
+		t.Fatalf("Expected first token to be allowed")
+	}
+	if ok, _ := bucket.AllowAt(now.Add(50*time.Millisecond), 1); ok {
+		t.Fatalf("Expected half token to be rejected")
+	}
+	if ok, _ := bucket.AllowAt(now.Add(150*time.Millisecond), 1); !ok {
+		t.Fatalf("Expected refilled token to be allowed")
+	}
+	if remaining := bucket.Remaining(now.Add(time.Hour)); remaining != 1 {
+		t.Errorf("Expected remaining capped at burst 1, got %d", remaining)
+	}
+}
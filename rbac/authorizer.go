@@ -0,0 +1,137 @@
+package rbac
+
+import (
+	"container/list"
+	"context"
+	metaset "meta/meta-set"
+	"sync"
+	"time"
+)
+
+const defaultCacheSize = 10000
+
+type cachedGrants struct {
+	subject  string
+	grants   *Grants
+	expireAt time.Time
+}
+
+// Authorizer 从 Source 读取主体的角色与权限，并按 cacheTtl 缓存
+// 缓存的主体数超过 cacheSize 时淘汰最久未使用的主体
+type Authorizer struct {
+	source    Source
+	cacheTtl  time.Duration
+	cacheSize int
+	now       func() time.Time
+
+	mutex sync.Mutex
+	cache map[string]*list.Element
+	lru   *list.List // 元素为 *cachedGrants，最近使用的在前
+}
+
+// NewAuthorizer cacheTtl 为 0 时不缓存
+func NewAuthorizer(source Source, cacheTtl time.Duration) *Authorizer {
+	return &Authorizer{
+		source:    source,
+		cacheTtl:  cacheTtl,
+		cacheSize: defaultCacheSize,
+		now:       time.Now,
+		cache:     make(map[string]*list.Element),
+		lru:       list.New(),
+	}
+}
+
+// SetCacheSize 设置最多缓存的主体数，默认为 10000
+func (a *Authorizer) SetCacheSize(cacheSize int) *Authorizer {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if cacheSize > 0 {
+		a.cacheSize = cacheSize
+		a.evictUnsafe()
+	}
+	return a
+}
+
+func (a *Authorizer) GetGrants(ctx context.Context, subject string) (*Grants, error) {
+	if a.cacheTtl > 0 {
+		if grants, ok := a.getCached(subject); ok {
+			return grants, nil
+		}
+	}
+	roles, err := a.source.GetSubjectRoles(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	grants := &Grants{
+		Subject: subject,
+		Roles:   metaset.FromSlice(roles),
+	}
+	for role := range grants.Roles {
+		permissions, err := a.source.GetRolePermissions(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+		grants.Permissions = append(grants.Permissions, permissions...)
+	}
+	if a.cacheTtl > 0 {
+		a.setCached(subject, grants)
+	}
+	return grants, nil
+}
+
+func (a *Authorizer) getCached(subject string) (*Grants, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	element, ok := a.cache[subject]
+	if !ok {
+		return nil, false
+	}
+	cached := element.Value.(*cachedGrants)
+	if !a.now().Before(cached.expireAt) {
+		a.lru.Remove(element)
+		delete(a.cache, subject)
+		return nil, false
+	}
+	a.lru.MoveToFront(element)
+	return cached.grants, true
+}
+
+func (a *Authorizer) setCached(subject string, grants *Grants) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	cached := &cachedGrants{
+		subject:  subject,
+		grants:   grants,
+		expireAt: a.now().Add(a.cacheTtl),
+	}
+	if element, ok := a.cache[subject]; ok {
+		element.Value = cached
+		a.lru.MoveToFront(element)
+		return
+	}
+	a.cache[subject] = a.lru.PushFront(cached)
+	a.evictUnsafe()
+}
+
+func (a *Authorizer) evictUnsafe() {
+	for a.lru.Len() > a.cacheSize {
+		element := a.lru.Back()
+		a.lru.Remove(element)
+		delete(a.cache, element.Value.(*cachedGrants).subject)
+	}
+}
+
+// Invalidate 清除主体的缓存，subject 为空时清除全部
+func (a *Authorizer) Invalidate(subject string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if subject == "" {
+		a.cache = make(map[string]*list.Element)
+		a.lru.Init()
+		return
+	}
+	if element, ok := a.cache[subject]; ok {
+		a.lru.Remove(element)
+		delete(a.cache, subject)
+	}
+}
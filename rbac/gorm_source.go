@@ -0,0 +1,48 @@
+package rbac
+
+import (
+	"context"
+	metaerror "meta/meta-error"
+
+	"gorm.io/gorm"
+)
+
+// GormSource 基于数据库的权限来源
+// 主体角色表包含 subject 与 role 列，角色权限表包含 role 与 permission 列
+type GormSource struct {
+	Db                  *gorm.DB
+	SubjectRoleTable    string // 默认为 rbac_subject_role
+	RolePermissionTable string // 默认为 rbac_role_permission
+}
+
+func NewGormSource(db *gorm.DB) *GormSource {
+	return &GormSource{
+		Db:                  db,
+		SubjectRoleTable:    "rbac_subject_role",
+		RolePermissionTable: "rbac_role_permission",
+	}
+}
+
+func (s *GormSource) GetSubjectRoles(ctx context.Context, subject string) ([]string, error) {
+	var roles []string
+	err := s.Db.WithContext(ctx).
+		Table(s.SubjectRoleTable).
+		Where("subject = ?", subject).
+		Pluck("role", &roles).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "get subject roles failed, subject:%s", subject)
+	}
+	return roles, nil
+}
+
+func (s *GormSource) GetRolePermissions(ctx context.Context, role string) ([]string, error) {
+	var permissions []string
+	err := s.Db.WithContext(ctx).
+		Table(s.RolePermissionTable).
+		Where("role = ?", role).
+		Pluck("permission", &permissions).Error
+	if err != nil {
+		return nil, metaerror.Wrap(err, "get role permissions failed, role:%s", role)
+	}
+	return permissions, nil
+}
@@ -0,0 +1,46 @@
+package rbac
+
+import metaset "meta/meta-set"
+
+// Grants 某个主体拥有的角色与权限
+type Grants struct {
+	Subject     string
+	Roles       metaset.Set[string]
+	Permissions []string
+}
+
+func (g *Grants) HasRole(role string) bool {
+	return g.Roles.Contains(role)
+}
+
+// HasAnyRole roles 为空时视为满足
+func (g *Grants) HasAnyRole(roles ...string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		if g.Roles.Contains(role) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Grants) HasPermission(permission string) bool {
+	for _, granted := range g.Permissions {
+		if MatchPermission(granted, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermissions 需要拥有全部权限
+func (g *Grants) HasPermissions(permissions ...string) bool {
+	for _, permission := range permissions {
+		if !g.HasPermission(permission) {
+			return false
+		}
+	}
+	return true
+}
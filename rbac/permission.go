@@ -0,0 +1,57 @@
+package rbac
+
+import "strings"
+
+const (
+	permissionSeparator = ":"
+	permissionWildcard  = "*"
+)
+
+// MatchPermission 判断授予的权限是否覆盖所需权限
+// 权限格式为 资源:操作[:范围]，按段比较，* 匹配任意单段，末尾的 * 匹配剩余所有段
+// 如 project:* 覆盖 project:edit:42，project:*:42 覆盖 project:edit:42
+func MatchPermission(granted string, required string) bool {
+	if granted == permissionWildcard || granted == required {
+		return true
+	}
+	grantedParts := strings.Split(granted, permissionSeparator)
+	requiredParts := strings.Split(required, permissionSeparator)
+	for i, grantedPart := range grantedParts {
+		if i >= len(requiredParts) {
+			return false
+		}
+		if grantedPart == permissionWildcard {
+			if i == len(grantedParts)-1 {
+				return true
+			}
+			continue
+		}
+		if grantedPart != requiredParts[i] {
+			return false
+		}
+	}
+	return len(grantedParts) == len(requiredParts)
+}
+
+// ExpandPermission 将权限中的 {name} 替换为参数值，用于按路径参数限定范围
+func ExpandPermission(permission string, getParam func(name string) string) string {
+	if !strings.Contains(permission, "{") {
+		return permission
+	}
+	var builder strings.Builder
+	for {
+		start := strings.Index(permission, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(permission[start:], "}")
+		if end < 0 {
+			break
+		}
+		builder.WriteString(permission[:start])
+		builder.WriteString(getParam(permission[start+1 : start+end]))
+		permission = permission[start+end+1:]
+	}
+	builder.WriteString(permission)
+	return builder.String()
+}
@@ -0,0 +1,124 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMatchPermission(t *testing.T) {
+	tests := []struct {
+		granted  string
+		required string
+		want     bool
+	}{
+		{"*", "project:edit", true},
+		{"project:edit", "project:edit", true},
+		{"project:*", "project:edit", true},
+		{"project:*", "project:edit:42", true},
+		{"project:*:42", "project:edit:42", true},
+		{"project:*:42", "project:edit:43", false},
+		{"project:edit", "project:edit:42", false},
+		{"project:edit:42", "project:edit", false},
+		{"project:view", "project:edit", false},
+		{"user:*", "project:edit", false},
+	}
+	for _, test := range tests {
+		if got := MatchPermission(test.granted, test.required); got != test.want {
+			t.Errorf("MatchPermission(%q, %q) = %v, want %v", test.granted, test.required, got, test.want)
+		}
+	}
+}
+
+func TestExpandPermission(t *testing.T) {
+	params := map[string]string{"id": "42", "org": "meta"}
+	getParam := func(name string) string {
+		return params[name]
+	}
+	if got := ExpandPermission("org:{org}:project:edit:{id}", getParam); got != "org:meta:project:edit:42" {
+		t.Errorf("unexpected expand result: %s", got)
+	}
+	if got := ExpandPermission("project:edit", getParam); got != "project:edit" {
+		t.Errorf("unexpected expand result: %s", got)
+	}
+}
+
+func TestStaticSource(t *testing.T) {
+	source, err := NewStaticSource(
+		&StaticConfig{
+			Roles: map[string]*StaticRole{
+				"viewer": {Permissions: []string{"project:view"}},
+				"editor": {Permissions: []string{"project:edit"}, Inherits: []string{"viewer"}},
+				"admin":  {Permissions: []string{"*"}},
+			},
+			Subjects:     map[string][]string{"alice": {"editor"}},
+			DefaultRoles: []string{"viewer"},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizer := NewAuthorizer(source, 0)
+	grants, err := authorizer.GetGrants(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !grants.HasRole("editor") || !grants.HasPermissions("project:edit", "project:view") {
+		t.Errorf("alice should be an editor with inherited permissions: %+v", grants)
+	}
+	if grants.HasPermission("user:delete") {
+		t.Error("alice should not have user:delete")
+	}
+	grants, err = authorizer.GetGrants(context.Background(), "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !grants.HasPermission("project:view") || grants.HasPermission("project:edit") {
+		t.Errorf("bob should only have default permissions: %+v", grants)
+	}
+
+	_, err = NewStaticSource(
+		&StaticConfig{
+			Roles: map[string]*StaticRole{
+				"a": {Inherits: []string{"b"}},
+				"b": {Inherits: []string{"a"}},
+			},
+		},
+	)
+	if err == nil {
+		t.Error("inherit cycle should fail")
+	}
+}
+
+type countingSource struct {
+	calls int
+}
+
+func (s *countingSource) GetSubjectRoles(context.Context, string) ([]string, error) {
+	s.calls++
+	return []string{"viewer"}, nil
+}
+
+func (s *countingSource) GetRolePermissions(context.Context, string) ([]string, error) {
+	return []string{"project:view"}, nil
+}
+
+func TestAuthorizerCacheSize(t *testing.T) {
+	source := &countingSource{}
+	authorizer := NewAuthorizer(source, time.Minute).SetCacheSize(2)
+	ctx := context.Background()
+	for _, subject := range []string{"a", "b", "a", "c"} {
+		if _, err := authorizer.GetGrants(ctx, subject); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if source.calls != 3 || len(authorizer.cache) != 2 {
+		t.Fatalf("expected 3 source calls and 2 cached subjects, got %d %d", source.calls, len(authorizer.cache))
+	}
+	// b 最久未使用，已被淘汰
+	_, _ = authorizer.GetGrants(ctx, "a")
+	_, _ = authorizer.GetGrants(ctx, "b")
+	if source.calls != 4 {
+		t.Errorf("expected evicted subject to be reloaded, got %d calls", source.calls)
+	}
+}
@@ -0,0 +1,11 @@
+package rbac
+
+import "context"
+
+// Source 权限数据来源
+type Source interface {
+	// GetSubjectRoles 返回主体直接拥有的角色
+	GetSubjectRoles(ctx context.Context, subject string) ([]string, error)
+	// GetRolePermissions 返回角色拥有的权限，包含继承的权限
+	GetRolePermissions(ctx context.Context, role string) ([]string, error)
+}
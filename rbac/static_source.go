@@ -0,0 +1,87 @@
+package rbac
+
+import (
+	"context"
+	metaerror "meta/meta-error"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+type StaticRole struct {
+	Permissions []string `yaml:"permissions"` // 权限列表
+	Inherits    []string `yaml:"inherits"`    // 继承的角色
+}
+
+type StaticConfig struct {
+	Roles        map[string]*StaticRole `yaml:"roles"`         // 角色定义
+	Subjects     map[string][]string    `yaml:"subjects"`      // 主体拥有的角色
+	DefaultRoles []string               `yaml:"default-roles"` // 所有已鉴权主体默认拥有的角色
+}
+
+// StaticSource 基于静态配置的权限来源
+type StaticSource struct {
+	config          *StaticConfig
+	rolePermissions map[string][]string
+}
+
+func LoadStaticSource(path string) (*StaticSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "failed to read rbac config file: %s", path)
+	}
+	config := &StaticConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, metaerror.Wrap(err, "failed to parse rbac config file: %s", path)
+	}
+	return NewStaticSource(config)
+}
+
+// NewStaticSource 展开角色继承，存在循环继承或未定义的角色时返回错误
+func NewStaticSource(config *StaticConfig) (*StaticSource, error) {
+	source := &StaticSource{
+		config:          config,
+		rolePermissions: make(map[string][]string, len(config.Roles)),
+	}
+	for role := range config.Roles {
+		permissions, err := source.resolveRole(role, nil)
+		if err != nil {
+			return nil, err
+		}
+		source.rolePermissions[role] = permissions
+	}
+	return source, nil
+}
+
+func (s *StaticSource) resolveRole(role string, visiting []string) ([]string, error) {
+	for _, v := range visiting {
+		if v == role {
+			return nil, metaerror.New("rbac role inherit cycle: %v -> %s", visiting, role)
+		}
+	}
+	staticRole, ok := s.config.Roles[role]
+	if !ok {
+		return nil, metaerror.New("rbac role not defined: %s", role)
+	}
+	if staticRole == nil {
+		return nil, nil
+	}
+	permissions := append([]string{}, staticRole.Permissions...)
+	for _, inherit := range staticRole.Inherits {
+		inherited, err := s.resolveRole(inherit, append(visiting, role))
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, inherited...)
+	}
+	return permissions, nil
+}
+
+func (s *StaticSource) GetSubjectRoles(_ context.Context, subject string) ([]string, error) {
+	roles := append([]string{}, s.config.DefaultRoles...)
+	return append(roles, s.config.Subjects[subject]...), nil
+}
+
+func (s *StaticSource) GetRolePermissions(_ context.Context, role string) ([]string, error) {
+	return s.rolePermissions[role], nil
+}
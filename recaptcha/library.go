@@ -2,44 +2,20 @@ package recaptcha
 
 import (
 	"context"
-	"encoding/json"
-	"io"
-	metapanic "meta/meta-panic"
-	"net/http"
-	"net/url"
+	"errors"
+	"meta/captcha"
 )
 
+// IsRecaptchaTokenValid 可使用 captcha.NewRecaptchaVerifier 配置分数、action 与 hostname 校验
 func IsRecaptchaTokenValid(ctx context.Context, secret string, response string, ip string) (bool, error) {
-	verifyUrl := "https://recaptcha.net/recaptcha/api/siteverify"
-	formData := url.Values{}
-	formData.Set("secret", secret)
-	formData.Set("response", response)
-	formData.Set("remoteip", ip)
-
-	resp, err := http.PostForm(verifyUrl, formData)
-	if err != nil {
-		return false, err
-	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			metapanic.ProcessError(err)
-		}
-	}(resp.Body)
-	if resp.StatusCode != http.StatusOK {
+	verifier := captcha.NewRecaptchaVerifier(&captcha.Config{Secret: secret})
+	err := captcha.VerifyToken(ctx, verifier, response, ip)
+	// 保持原有约定，校验不通过或状态码异常时不返回错误
+	if errors.Is(err, captcha.ErrVerifyFailed) || errors.Is(err, captcha.ErrUnexpectedStatus) {
 		return false, nil
 	}
-	var verifyResponseData struct {
-		Success     bool     `json:"success"`
-		Score       float64  `json:"score"`
-		Action      string   `json:"action"`
-		ChallengeTS string   `json:"challenge_ts"`
-		Hostname    string   `json:"hostname"`
-		ErrorCodes  []string `json:"error-codes"`
-	}
-	err = json.NewDecoder(resp.Body).Decode(&verifyResponseData)
 	if err != nil {
 		return false, err
 	}
-	return verifyResponseData.Success, nil
+	return true, nil
 }
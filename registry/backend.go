@@ -0,0 +1,14 @@
+package registry
+
+import (
+	"context"
+	"time"
+)
+
+// Backend 服务发现的存储后端
+type Backend interface {
+	// Register 注册或刷新节点，ttl 内未刷新的节点视为下线
+	Register(ctx context.Context, node *Node, ttl time.Duration) error
+	Deregister(ctx context.Context, module string, name string) error
+	GetNodes(ctx context.Context, module string) ([]*Node, error)
+}
@@ -0,0 +1,11 @@
+package registry
+
+import "time"
+
+type Config struct {
+	Prefix            string            `yaml:"prefix"`             // Redis 键前缀
+	Ttl               time.Duration     `yaml:"ttl"`                // 节点过期时间
+	HeartbeatInterval time.Duration     `yaml:"heartbeat-interval"` // 心跳间隔，默认 ttl 的三分之一
+	WatchInterval     time.Duration     `yaml:"watch-interval"`     // 监听其他模块时的轮询间隔
+	Metadata          map[string]string `yaml:"metadata"`           // 节点附加信息
+}
@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryBackend 进程内的服务发现后端，用于测试或单机运行
+type MemoryBackend struct {
+	mutex   sync.RWMutex
+	modules map[string]map[string]*memoryEntry
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	node     Node
+	expireAt time.Time
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		modules: make(map[string]map[string]*memoryEntry),
+		now:     time.Now,
+	}
+}
+
+func (b *MemoryBackend) Register(ctx context.Context, node *Node, ttl time.Duration) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	nodes, ok := b.modules[node.Module]
+	if !ok {
+		nodes = make(map[string]*memoryEntry)
+		b.modules[node.Module] = nodes
+	}
+	nodes[node.Name] = &memoryEntry{
+		node:     *node,
+		expireAt: b.now().Add(ttl),
+	}
+	return nil
+}
+
+func (b *MemoryBackend) Deregister(ctx context.Context, module string, name string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if nodes, ok := b.modules[module]; ok {
+		delete(nodes, name)
+	}
+	return nil
+}
+
+func (b *MemoryBackend) GetNodes(ctx context.Context, module string) ([]*Node, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	now := b.now()
+	var result []*Node
+	for _, entry := range b.modules[module] {
+		if now.After(entry.expireAt) {
+			continue
+		}
+		node := entry.node
+		result = append(result, &node)
+	}
+	sortNodes(result)
+	return result, nil
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(
+		nodes, func(i, j int) bool {
+			return nodes[i].Name < nodes[j].Name
+		},
+	)
+}
@@ -0,0 +1,40 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryBackendExpire(t *testing.T) {
+	now := time.Now()
+	backend := NewMemoryBackend()
+	backend.now = func() time.Time {
+		return now
+	}
+	ctx := context.Background()
+
+	_ = backend.Register(ctx, &Node{Module: "game", Name: "b", Ip: "10.0.0.2", RpcPort: 9000}, time.Second)
+	_ = backend.Register(ctx, &Node{Module: "game", Name: "a", Ip: "10.0.0.1", RpcPort: 9000}, 3*time.Second)
+	_ = backend.Register(ctx, &Node{Module: "web", Name: "a", Ip: "10.0.0.3"}, time.Second)
+
+	nodes, _ := backend.GetNodes(ctx, "game")
+	if len(nodes) != 2 || nodes[0].Name != "a" || nodes[1].Name != "b" {
+		t.Fatalf("Expected sorted nodes [a b], got %+v", nodes)
+	}
+	if address := nodes[0].GetRpcAddress(); address != "10.0.0.1:9000" {
+		t.Errorf("Expected rpc address 10.0.0.1:9000, got %s", address)
+	}
+
+	now = now.Add(2 * time.Second)
+	nodes, _ = backend.GetNodes(ctx, "game")
+	if len(nodes) != 1 || nodes[0].Name != "a" {
+		t.Fatalf("Expected only node a alive, got %+v", nodes)
+	}
+
+	_ = backend.Deregister(ctx, "game", "a")
+	nodes, _ = backend.GetNodes(ctx, "game")
+	if len(nodes) != 0 {
+		t.Errorf("Expected no nodes after deregister, got %+v", nodes)
+	}
+}
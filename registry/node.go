@@ -0,0 +1,38 @@
+package registry
+
+import (
+	"net"
+	"strconv"
+	"time"
+)
+
+// Node 注册到服务发现中的节点信息
+type Node struct {
+	Module     string            `json:"module"`
+	Name       string            `json:"name"`
+	Ip         string            `json:"ip"`
+	SocketPort int32             `json:"socket_port,omitempty"`
+	HttpPort   int32             `json:"http_port,omitempty"`
+	RpcPort    int32             `json:"rpc_port,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	UpdateTime time.Time         `json:"update_time"`
+}
+
+func (n *Node) GetSocketAddress() string {
+	return joinAddress(n.Ip, n.SocketPort)
+}
+
+func (n *Node) GetHttpAddress() string {
+	return joinAddress(n.Ip, n.HttpPort)
+}
+
+func (n *Node) GetRpcAddress() string {
+	return joinAddress(n.Ip, n.RpcPort)
+}
+
+func joinAddress(ip string, port int32) string {
+	if port <= 0 {
+		return ""
+	}
+	return net.JoinHostPort(ip, strconv.Itoa(int(port)))
+}
@@ -0,0 +1,124 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	metaerror "meta/meta-error"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBackend 基于 Redis 的服务发现后端
+// 每个节点保存为带过期时间的键 prefix:node:module:name，模块下的节点名单保存为集合 prefix:set:module
+type RedisBackend struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisBackend(client *redis.Client, prefix string) *RedisBackend {
+	if prefix == "" {
+		prefix = "meta:registry"
+	}
+	return &RedisBackend{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (b *RedisBackend) getModuleKey(module string) string {
+	return b.prefix + ":set:" + module
+}
+
+func (b *RedisBackend) getNodeKey(module string, name string) string {
+	return b.prefix + ":node:" + module + ":" + name
+}
+
+// removeExpiredScript 节点键仍不存在时才从名单中移除，避免删掉刚重新注册的节点
+// KEYS[1] 为名单，KEYS[i+1] 为 ARGV[i] 对应的节点键
+var removeExpiredScript = redis.NewScript(
+	`
+local removed = 0
+for i, name in ipairs(ARGV) do
+	if redis.call("EXISTS", KEYS[i + 1]) == 0 then
+		removed = removed + redis.call("SREM", KEYS[1], name)
+	end
+end
+return removed
+`,
+)
+
+func (b *RedisBackend) Register(ctx context.Context, node *Node, ttl time.Duration) error {
+	data, err := json.Marshal(node)
+	if err != nil {
+		return metaerror.Wrap(err, "marshal node failed")
+	}
+	_, err = b.client.TxPipelined(
+		ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, b.getNodeKey(node.Module, node.Name), data, ttl)
+			pipe.SAdd(ctx, b.getModuleKey(node.Module), node.Name)
+			return nil
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "register node failed, module:%s, name:%s", node.Module, node.Name)
+	}
+	return nil
+}
+
+func (b *RedisBackend) Deregister(ctx context.Context, module string, name string) error {
+	_, err := b.client.TxPipelined(
+		ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, b.getNodeKey(module, name))
+			pipe.SRem(ctx, b.getModuleKey(module), name)
+			return nil
+		},
+	)
+	if err != nil {
+		return metaerror.Wrap(err, "deregister node failed, module:%s, name:%s", module, name)
+	}
+	return nil
+}
+
+func (b *RedisBackend) GetNodes(ctx context.Context, module string) ([]*Node, error) {
+	names, err := b.client.SMembers(ctx, b.getModuleKey(module)).Result()
+	if err != nil {
+		return nil, metaerror.Wrap(err, "get module nodes failed, module:%s", module)
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = b.getNodeKey(module, name)
+	}
+	values, err := b.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, metaerror.Wrap(err, "get nodes failed, module:%s", module)
+	}
+	var result []*Node
+	expiredKeys := []string{b.getModuleKey(module)}
+	var expired []interface{}
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// 节点键已过期，从名单中清理
+			expiredKeys = append(expiredKeys, keys[i])
+			expired = append(expired, names[i])
+			continue
+		}
+		var node Node
+		if err := json.Unmarshal([]byte(data), &node); err != nil {
+			return nil, metaerror.Wrap(err, "unmarshal node failed, module:%s, name:%s", module, names[i])
+		}
+		result = append(result, &node)
+	}
+	if len(expired) > 0 {
+		if err := removeExpiredScript.Run(ctx, b.client, expiredKeys, expired...).Err(); err != nil {
+			slog.Warn("Registry remove expired nodes failed", "module", module, "err", err)
+		}
+	}
+	sortNodes(result)
+	return result, nil
+}
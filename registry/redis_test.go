@@ -0,0 +1,126 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedisBackend(t *testing.T) (*RedisBackend, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(
+		func() {
+			_ = client.Close()
+		},
+	)
+	return NewRedisBackend(client, ""), server
+}
+
+func TestRedisBackend(t *testing.T) {
+	backend, server := newTestRedisBackend(t)
+	ctx := context.Background()
+
+	_ = backend.Register(ctx, &Node{Module: "game", Name: "b", Ip: "10.0.0.2"}, time.Second)
+	_ = backend.Register(ctx, &Node{Module: "game", Name: "a", Ip: "10.0.0.1"}, 3*time.Second)
+	// 模块名包含 : 时不与其他模块的节点键冲突
+	if err := backend.Register(ctx, &Node{Module: "game:a", Name: "c", Ip: "10.0.0.3"}, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := backend.GetNodes(ctx, "game")
+	if err != nil || len(nodes) != 2 || nodes[0].Name != "a" || nodes[1].Name != "b" {
+		t.Fatalf("Expected sorted nodes [a b], got %+v %v", nodes, err)
+	}
+	if nodes, _ = backend.GetNodes(ctx, "game:a"); len(nodes) != 1 || nodes[0].Name != "c" {
+		t.Errorf("Expected node c, got %+v", nodes)
+	}
+
+	server.FastForward(2 * time.Second)
+	if nodes, _ = backend.GetNodes(ctx, "game"); len(nodes) != 1 || nodes[0].Name != "a" {
+		t.Fatalf("Expected expired node b removed, got %+v", nodes)
+	}
+	if members, _ := server.SMembers(backend.getModuleKey("game")); len(members) != 1 {
+		t.Errorf("Expected expired name removed from set, got %v", members)
+	}
+
+	if err := backend.Deregister(ctx, "game", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if nodes, _ = backend.GetNodes(ctx, "game"); len(nodes) != 0 {
+		t.Errorf("Expected no nodes after deregister, got %+v", nodes)
+	}
+}
+
+func TestRedisBackendKeepLiveNode(t *testing.T) {
+	backend, server := newTestRedisBackend(t)
+	ctx := context.Background()
+	_ = backend.Register(ctx, &Node{Module: "game", Name: "a"}, time.Minute)
+
+	// 节点键存在时，清理脚本不会移除名单中的名字
+	keys := []string{backend.getModuleKey("game"), backend.getNodeKey("game", "a")}
+	if err := removeExpiredScript.Run(ctx, backend.client, keys, "a").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if members, _ := server.SMembers(backend.getModuleKey("game")); len(members) != 1 {
+		t.Errorf("Expected live node kept in set, got %v", members)
+	}
+}
+
+func TestRedisBackendWatch(t *testing.T) {
+	backend, _ := newTestRedisBackend(t)
+	s := &Subsystem{
+		GetConfig: func() *Config {
+			return &Config{Ttl: time.Minute, WatchInterval: 5 * time.Millisecond}
+		},
+		GetBackend: func() Backend {
+			return backend
+		},
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = s.Stop()
+	}()
+
+	updates := make(chan []*Node, 10)
+	cancel, err := s.Watch(
+		"game", func(nodes []*Node) {
+			updates <- nodes
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	waitNodes := func(check func([]*Node) bool) {
+		t.Helper()
+		timeout := time.After(time.Second)
+		for {
+			select {
+			case nodes := <-updates:
+				if check(nodes) {
+					return
+				}
+			case <-timeout:
+				t.Fatal("Expected watch callback")
+			}
+		}
+	}
+	waitNodes(func(nodes []*Node) bool { return len(nodes) == 0 })
+
+	ctx := context.Background()
+	_ = backend.Register(ctx, &Node{Module: "game", Name: "a", Ip: "10.0.0.1"}, time.Minute)
+	waitNodes(func(nodes []*Node) bool { return len(nodes) == 1 })
+
+	// 只修改元数据也会回调
+	_ = backend.Register(ctx, &Node{Module: "game", Name: "a", Ip: "10.0.0.1", Metadata: map[string]string{"v": "2"}}, time.Minute)
+	waitNodes(func(nodes []*Node) bool { return len(nodes) == 1 && nodes[0].Metadata["v"] == "2" })
+
+	_ = backend.Deregister(ctx, "game", "a")
+	waitNodes(func(nodes []*Node) bool { return len(nodes) == 0 })
+}
@@ -0,0 +1,224 @@
+package registry
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"meta/engine"
+	"meta/host"
+	metaconfig "meta/meta-config"
+	metaerror "meta/meta-error"
+	metaredis "meta/meta-redis"
+	"meta/metaroutine"
+	"meta/subsystem"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTtl           = 15 * time.Second
+	defaultWatchInterval = 5 * time.Second
+)
+
+// Subsystem 服务注册与发现
+// 应在 Redis 之后、依赖服务发现的子系统之前注册，引擎逆序停止时先注销节点再关闭 Redis
+type Subsystem struct {
+	subsystem.Subsystem
+	GetConfig  func() *Config
+	GetBackend func() Backend // 为空时使用 metaredis
+
+	GetSocketPort func() int32
+	GetHttpPort   func() int32
+	GetRpcPort    func() int32
+
+	config    *Config
+	backend   Backend
+	node      *Node // 发布后不再修改，心跳时整体替换
+	nodeMutex sync.RWMutex
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func GetSubsystem() *Subsystem {
+	if thisSubsystem := engine.GetSubsystem[*Subsystem](); thisSubsystem != nil {
+		return thisSubsystem.(*Subsystem)
+	}
+	return nil
+}
+
+func (s *Subsystem) GetName() string {
+	return "Registry"
+}
+
+func (s *Subsystem) Start() error {
+	s.config = &Config{}
+	if s.GetConfig != nil {
+		if config := s.GetConfig(); config != nil {
+			s.config = config
+		}
+	}
+	if s.config.Ttl <= 0 {
+		s.config.Ttl = defaultTtl
+	}
+	if s.config.HeartbeatInterval <= 0 {
+		s.config.HeartbeatInterval = s.config.Ttl / 3
+	}
+	if s.config.WatchInterval <= 0 {
+		s.config.WatchInterval = defaultWatchInterval
+	}
+
+	if s.GetBackend != nil {
+		s.backend = s.GetBackend()
+	} else {
+		redisSubsystem := metaredis.GetSubsystem()
+		if redisSubsystem == nil || redisSubsystem.GetClient() == nil {
+			return metaerror.New("registry backend is nil and redis subsystem not found")
+		}
+		s.backend = NewRedisBackend(redisSubsystem.GetClient(), s.config.Prefix)
+	}
+
+	s.nodeMutex.Lock()
+	s.node = s.newNode()
+	s.nodeMutex.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	if err := s.heartbeat(ctx); err != nil {
+		cancel()
+		return err
+	}
+	node := s.GetNode()
+	slog.Info("Registry node registered", "module", node.Module, "name", node.Name, "ip", node.Ip)
+
+	s.wg.Add(1)
+	metaroutine.SafeGo(
+		"Registry heartbeat",
+		func() error {
+			defer s.wg.Done()
+			ticker := time.NewTicker(s.config.HeartbeatInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+					if err := s.heartbeat(ctx); err != nil && ctx.Err() == nil {
+						slog.Error("Registry heartbeat failed", "err", err)
+					}
+				}
+			}
+		},
+	)
+	return nil
+}
+
+func (s *Subsystem) Stop() error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	s.wg.Wait()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	node := s.GetNode()
+	return s.backend.Deregister(ctx, node.Module, node.Name)
+}
+
+// GetNode 返回当前节点的注册信息，返回值不应被修改
+func (s *Subsystem) GetNode() *Node {
+	s.nodeMutex.RLock()
+	defer s.nodeMutex.RUnlock()
+	return s.node
+}
+
+// GetNodes 返回指定模块的在线节点
+func (s *Subsystem) GetNodes(ctx context.Context, module string) ([]*Node, error) {
+	return s.backend.GetNodes(ctx, module)
+}
+
+// Watch 定期拉取指定模块的节点，节点列表变化时回调
+// 首次回调在返回前同步执行，返回的函数用于取消监听
+func (s *Subsystem) Watch(module string, callback func(nodes []*Node)) (func(), error) {
+	if s.config == nil || s.backend == nil {
+		return nil, metaerror.New("registry subsystem not started, module:%s", module)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var last []*Node
+	poll := func() {
+		nodes, err := s.backend.GetNodes(ctx, module)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("Registry watch failed", "module", module, "err", err)
+			}
+			return
+		}
+		if last != nil && isNodesEqual(last, nodes) {
+			return
+		}
+		last = nodes
+		if last == nil {
+			last = []*Node{}
+		}
+		callback(nodes)
+	}
+	poll()
+	metaroutine.SafeGo(
+		"Registry watch",
+		func() error {
+			ticker := time.NewTicker(s.config.WatchInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+					poll()
+				}
+			}
+		},
+	)
+	return cancel, nil
+}
+
+func (s *Subsystem) heartbeat(ctx context.Context) error {
+	s.nodeMutex.Lock()
+	node := *s.node
+	node.UpdateTime = time.Now()
+	s.node = &node
+	s.nodeMutex.Unlock()
+	return s.backend.Register(ctx, &node, s.config.Ttl)
+}
+
+func (s *Subsystem) newNode() *Node {
+	node := &Node{
+		Module:   metaconfig.GetModuleName(),
+		Name:     metaconfig.GetNodeName(),
+		Ip:       host.GetLocalIp(),
+		Metadata: s.config.Metadata,
+	}
+	if s.GetSocketPort != nil {
+		node.SocketPort = s.GetSocketPort()
+	}
+	if s.GetHttpPort != nil {
+		node.HttpPort = s.GetHttpPort()
+	}
+	if s.GetRpcPort != nil {
+		node.RpcPort = s.GetRpcPort()
+	}
+	return node
+}
+
+// isNodesEqual 比较两个已排序的节点列表，忽略心跳时间，元数据变化视为不同
+func isNodesEqual(a []*Node, b []*Node) bool {
+	return slices.EqualFunc(
+		a, b, func(x *Node, y *Node) bool {
+			return x.Module == y.Module &&
+				x.Name == y.Name &&
+				x.Ip == y.Ip &&
+				x.SocketPort == y.SocketPort &&
+				x.HttpPort == y.HttpPort &&
+				x.RpcPort == y.RpcPort &&
+				maps.Equal(x.Metadata, y.Metadata)
+		},
+	)
+}
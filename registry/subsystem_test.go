@@ -0,0 +1,40 @@
+package registry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubsystemHeartbeat(t *testing.T) {
+	s := &Subsystem{}
+	if _, err := s.Watch("game", func([]*Node) {}); err == nil {
+		t.Error("watch before start should fail")
+	}
+
+	backend := NewMemoryBackend()
+	s.GetConfig = func() *Config {
+		return &Config{Ttl: time.Second, HeartbeatInterval: time.Millisecond}
+	}
+	s.GetBackend = func() Backend {
+		return backend
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// 心跳期间并发读取节点，配合 -race 检查
+	node := s.GetNode()
+	first := node.UpdateTime
+	deadline := time.Now().Add(time.Second)
+	for s.GetNode().UpdateTime.Equal(first) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if s.GetNode().UpdateTime.Equal(first) {
+		t.Error("heartbeat should refresh update time")
+	}
+	if !node.UpdateTime.Equal(first) {
+		t.Error("published node should not change")
+	}
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
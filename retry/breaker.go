@@ -0,0 +1,259 @@
+package retry
+
+import (
+	"errors"
+	"log/slog"
+	metaerror "meta/meta-error"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// breakerBuckets 滑动窗口划分的桶数
+const breakerBuckets = 10
+
+// ErrCircuitOpen 熔断中，标记为不可重试，使重试循环立即结束
+var ErrCircuitOpen = metaerror.WithRetryable(
+	metaerror.WrapCode(errors.New("circuit breaker open"), http.StatusServiceUnavailable), false,
+)
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+type BreakerConfig struct {
+	Window           time.Duration `yaml:"window"`             // 统计失败率的滑动窗口，默认为 30s
+	MinRequests      int           `yaml:"min-requests"`       // 窗口内请求数达到后才计算失败率，默认为 10
+	FailureRate      float64       `yaml:"failure-rate"`       // 失败率达到后熔断，默认为 0.5
+	OpenTimeout      time.Duration `yaml:"open-timeout"`       // 熔断后经过该时间进入半开，默认为 30s
+	HalfOpenRequests int           `yaml:"half-open-requests"` // 半开时允许的探测请求数，全部成功后恢复，默认为 1
+}
+
+func (c *BreakerConfig) setDefault() {
+	if c.Window <= 0 {
+		c.Window = 30 * time.Second
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = 10
+	}
+	if c.FailureRate <= 0 {
+		c.FailureRate = 0.5
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = 30 * time.Second
+	}
+	if c.HalfOpenRequests <= 0 {
+		c.HalfOpenRequests = 1
+	}
+}
+
+type breakerBucket struct {
+	start    int64
+	success  int
+	failures int
+}
+
+// Breaker 熔断器，关闭时按窗口内失败率熔断，熔断一段时间后半开放行少量请求探测
+type Breaker struct {
+	name   string
+	config BreakerConfig
+	now    func() time.Time
+
+	// IsFailure 判断结果是否计为失败，默认不可重试的错误（如参数错误、404）不计为失败
+	IsFailure func(err error) bool
+
+	mutex           sync.Mutex
+	state           BreakerState
+	openedAt        time.Time
+	buckets         [breakerBuckets]breakerBucket
+	halfOpenPending int
+	halfOpenSuccess int
+	generation      int // 每次状态变化加一，忽略之前状态下发出的请求结果
+}
+
+func NewBreaker(name string, config *BreakerConfig) *Breaker {
+	b := &Breaker{
+		name:      name,
+		now:       time.Now,
+		IsFailure: isBreakerFailure,
+	}
+	if config != nil {
+		b.config = *config
+	}
+	b.config.setDefault()
+	return b
+}
+
+func isBreakerFailure(err error) bool {
+	return err != nil && metaerror.IsRetryable(err)
+}
+
+func (b *Breaker) GetName() string {
+	return b.name
+}
+
+func (b *Breaker) GetState() BreakerState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.checkOpenTimeout()
+	return b.state
+}
+
+// Allow 熔断中返回 ErrCircuitOpen，否则返回的 done 需在请求结束后以结果调用
+func (b *Breaker) Allow() (done func(err error), err error) {
+	record, err := b.allow()
+	if err != nil {
+		return nil, err
+	}
+	return func(err error) {
+		record(b.IsFailure(err))
+	}, nil
+}
+
+func (b *Breaker) allow() (record func(failure bool), err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.checkOpenTimeout()
+	switch b.state {
+	case BreakerOpen:
+		breakerRejected.WithLabelValues(b.name).Inc()
+		return nil, metaerror.Wrap(ErrCircuitOpen, "breaker: %s", b.name)
+	case BreakerHalfOpen:
+		if b.halfOpenPending+b.halfOpenSuccess >= b.config.HalfOpenRequests {
+			breakerRejected.WithLabelValues(b.name).Inc()
+			return nil, metaerror.Wrap(ErrCircuitOpen, "breaker half-open: %s", b.name)
+		}
+		b.halfOpenPending++
+		return b.onceRecord(b.generation), nil
+	default:
+		return b.onceRecord(b.generation), nil
+	}
+}
+
+// Execute 在熔断器保护下执行 f，f panic 时记为失败后继续 panic，半开状态的计数不会泄漏
+func (b *Breaker) Execute(f func() error) (err error) {
+	record, err := b.allow()
+	if err != nil {
+		return err
+	}
+	panicked := true
+	defer func() {
+		if panicked {
+			record(true)
+			return
+		}
+		record(b.IsFailure(err))
+	}()
+	err = f()
+	panicked = false
+	return err
+}
+
+func (b *Breaker) onceRecord(generation int) func(failure bool) {
+	var once sync.Once
+	return func(failure bool) {
+		once.Do(
+			func() {
+				b.record(generation, failure)
+			},
+		)
+	}
+}
+
+func (b *Breaker) record(generation int, failure bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if generation != b.generation {
+		return
+	}
+	if b.state == BreakerHalfOpen {
+		b.halfOpenPending--
+		if failure {
+			b.setState(BreakerOpen)
+			return
+		}
+		b.halfOpenSuccess++
+		if b.halfOpenSuccess >= b.config.HalfOpenRequests {
+			b.setState(BreakerClosed)
+		}
+		return
+	}
+	bucket := b.currentBucket()
+	if failure {
+		bucket.failures++
+	} else {
+		bucket.success++
+	}
+	total, failures := b.count()
+	if total >= b.config.MinRequests && float64(failures)/float64(total) >= b.config.FailureRate {
+		b.setState(BreakerOpen)
+	}
+}
+
+func (b *Breaker) bucketDuration() int64 {
+	duration := int64(b.config.Window / breakerBuckets)
+	if duration <= 0 {
+		duration = 1
+	}
+	return duration
+}
+
+func (b *Breaker) currentBucket() *breakerBucket {
+	duration := b.bucketDuration()
+	start := b.now().UnixNano() / duration * duration
+	bucket := &b.buckets[(start/duration)%breakerBuckets]
+	if bucket.start != start {
+		*bucket = breakerBucket{start: start}
+	}
+	return bucket
+}
+
+func (b *Breaker) count() (total int, failures int) {
+	oldest := b.now().UnixNano() - int64(b.config.Window)
+	for _, bucket := range b.buckets {
+		if bucket.start > oldest {
+			total += bucket.success + bucket.failures
+			failures += bucket.failures
+		}
+	}
+	return total, failures
+}
+
+func (b *Breaker) checkOpenTimeout() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		b.setState(BreakerHalfOpen)
+	}
+}
+
+func (b *Breaker) setState(state BreakerState) {
+	if b.state == state {
+		return
+	}
+	slog.Warn("Breaker state changed", "name", b.name, "from", b.state.String(), "to", state.String())
+	b.state = state
+	b.generation++
+	b.halfOpenPending = 0
+	b.halfOpenSuccess = 0
+	switch state {
+	case BreakerOpen:
+		b.openedAt = b.now()
+	case BreakerClosed:
+		b.buckets = [breakerBuckets]breakerBucket{}
+	}
+	breakerState.WithLabelValues(b.name).Set(float64(state))
+}
@@ -0,0 +1,146 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	metaerror "meta/meta-error"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	breaker := NewBreaker(
+		"test", &BreakerConfig{
+			Window: 10 * time.Second, MinRequests: 4, FailureRate: 0.5, OpenTimeout: 5 * time.Second,
+		},
+	)
+	breaker.now = func() time.Time { return now }
+	failure := errors.New("failure")
+
+	_ = breaker.Execute(func() error { return nil })
+	_ = breaker.Execute(func() error { return nil })
+	_ = breaker.Execute(func() error { return failure })
+	_ = breaker.Execute(func() error { return metaerror.ErrNotFound })
+	if breaker.GetState() != BreakerClosed {
+		t.Fatal("non-retryable errors should not count as failures")
+	}
+	_ = breaker.Execute(func() error { return failure })
+	_ = breaker.Execute(func() error { return failure })
+	if breaker.GetState() != BreakerOpen {
+		t.Fatal("breaker should open at failure rate")
+	}
+	if err := breaker.Execute(func() error { return nil }); !errors.Is(err, ErrCircuitOpen) || metaerror.IsRetryable(err) {
+		t.Fatalf("open breaker should reject with non-retryable error: %v", err)
+	}
+
+	now = now.Add(5 * time.Second)
+	done, err := breaker.Allow()
+	if err != nil || breaker.GetState() != BreakerHalfOpen {
+		t.Fatalf("breaker should be half-open: %v", err)
+	}
+	if _, err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("half-open should allow only one probe")
+	}
+	done(failure)
+	if breaker.GetState() != BreakerOpen {
+		t.Fatal("failed probe should reopen")
+	}
+
+	now = now.Add(5 * time.Second)
+	_ = breaker.Execute(func() error { return nil })
+	if breaker.GetState() != BreakerClosed {
+		t.Fatal("successful probe should close")
+	}
+
+	// 窗口之外的失败不再计入
+	for i := 0; i < 3; i++ {
+		_ = breaker.Execute(func() error { return failure })
+	}
+	now = now.Add(11 * time.Second)
+	_ = breaker.Execute(func() error { return failure })
+	if breaker.GetState() != BreakerClosed {
+		t.Fatal("failures outside window should expire")
+	}
+}
+
+func TestBulkhead(t *testing.T) {
+	bulkhead := NewBulkhead("test", &BulkheadConfig{MaxConcurrent: 1, MaxWait: 10 * time.Millisecond})
+	release, err := bulkhead.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bulkhead.Acquire(context.Background()); !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("full bulkhead should reject: %v", err)
+	}
+	go func() {
+		time.Sleep(time.Millisecond)
+		release()
+	}()
+	if err := bulkhead.Execute(context.Background(), func() error { return nil }); err != nil {
+		t.Fatalf("bulkhead should wait for released slot: %v", err)
+	}
+
+	calls := 0
+	err = TryRetryWhenErr(
+		"test", 5, func(int) error {
+			calls++
+			return metaerror.Wrap(ErrCircuitOpen)
+		},
+	)
+	if calls != 1 || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("retry should stop on open breaker: %d %v", calls, err)
+	}
+}
+
+func TestBreakerPanic(t *testing.T) {
+	now := time.Unix(1000, 0)
+	breaker := NewBreaker(
+		"test", &BreakerConfig{
+			Window: 10 * time.Second, MinRequests: 1, FailureRate: 0.5, OpenTimeout: 5 * time.Second,
+		},
+	)
+	breaker.now = func() time.Time { return now }
+	_ = breaker.Execute(func() error { return errors.New("failure") })
+	now = now.Add(5 * time.Second)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic should propagate")
+			}
+		}()
+		_ = breaker.Execute(func() error { panic("probe") })
+	}()
+	if breaker.GetState() != BreakerOpen {
+		t.Fatal("panicked probe should reopen")
+	}
+	now = now.Add(5 * time.Second)
+	if err := breaker.Execute(func() error { return nil }); err != nil || breaker.GetState() != BreakerClosed {
+		t.Fatalf("next probe should be allowed: %v", err)
+	}
+}
+
+func TestProtectWithoutBulkhead(t *testing.T) {
+	name := "test:protect"
+	if GetBulkhead(name) != nil {
+		t.Fatal("bulkhead should be disabled by default")
+	}
+	release := make(chan struct{})
+	errs := make(chan error, 100)
+	for i := 0; i < 100; i++ {
+		go func() {
+			errs <- Protect(
+				context.Background(), name, func() error {
+					<-release
+					return nil
+				},
+			)
+		}()
+	}
+	close(release)
+	for i := 0; i < 100; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("burst should not be rejected: %v", err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	metaerror "meta/meta-error"
+	"net/http"
+	"time"
+)
+
+// ErrBulkheadFull 并发已满，标记为不可重试，避免重试继续堆积协程
+var ErrBulkheadFull = metaerror.WithRetryable(
+	metaerror.WrapCode(errors.New("bulkhead full"), http.StatusServiceUnavailable), false,
+)
+
+type BulkheadConfig struct {
+	MaxConcurrent int           `yaml:"max-concurrent"` // 最大并发数，默认为 64
+	MaxWait       time.Duration `yaml:"max-wait"`       // 等待空位的最长时间，0 为不等待
+}
+
+func (c *BulkheadConfig) setDefault() {
+	if c.MaxConcurrent <= 0 {
+		c.MaxConcurrent = 64
+	}
+}
+
+// Bulkhead 限制同一依赖的并发调用数，依赖变慢时不会耗尽调用方的协程
+type Bulkhead struct {
+	name    string
+	maxWait time.Duration
+	slots   chan struct{}
+}
+
+func NewBulkhead(name string, config *BulkheadConfig) *Bulkhead {
+	c := BulkheadConfig{}
+	if config != nil {
+		c = *config
+	}
+	c.setDefault()
+	return &Bulkhead{
+		name:    name,
+		maxWait: c.MaxWait,
+		slots:   make(chan struct{}, c.MaxConcurrent),
+	}
+}
+
+func (b *Bulkhead) GetName() string {
+	return b.name
+}
+
+// Acquire 获取成功后需调用 release 归还
+func (b *Bulkhead) Acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case b.slots <- struct{}{}:
+		return b.release, nil
+	default:
+	}
+	if b.maxWait > 0 {
+		timer := time.NewTimer(b.maxWait)
+		defer timer.Stop()
+		select {
+		case b.slots <- struct{}{}:
+			return b.release, nil
+		case <-ctx.Done():
+			return nil, metaerror.Wrap(ctx.Err(), "bulkhead wait canceled: %s", b.name)
+		case <-timer.C:
+		}
+	}
+	bulkheadRejected.WithLabelValues(b.name).Inc()
+	return nil, metaerror.Wrap(ErrBulkheadFull, "bulkhead: %s", b.name)
+}
+
+func (b *Bulkhead) release() {
+	<-b.slots
+}
+
+// Execute 在并发限制内执行 f
+func (b *Bulkhead) Execute(ctx context.Context, f func() error) error {
+	release, err := b.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return f()
+}
+
+// GetInFlight 返回正在执行的调用数
+func (b *Bulkhead) GetInFlight() int {
+	return len(b.slots)
+}
@@ -0,0 +1,95 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	guardMutex            sync.Mutex
+	defaultBreakerConfig  BreakerConfig
+	defaultBulkheadConfig *BulkheadConfig
+	breakerConfigs        = make(map[string]*BreakerConfig)
+	bulkheadConfigs       = make(map[string]*BulkheadConfig)
+	breakers              = make(map[string]*Breaker)
+	bulkheads             = make(map[string]*Bulkhead)
+)
+
+// SetDefaultBreakerConfig 设置未单独配置的熔断器使用的配置，只影响之后创建的熔断器
+func SetDefaultBreakerConfig(config BreakerConfig) {
+	guardMutex.Lock()
+	defer guardMutex.Unlock()
+	defaultBreakerConfig = config
+}
+
+// SetBreakerConfig 为指定名称的熔断器设置配置，已创建的熔断器会被替换
+func SetBreakerConfig(name string, config BreakerConfig) {
+	guardMutex.Lock()
+	defer guardMutex.Unlock()
+	breakerConfigs[name] = &config
+	delete(breakers, name)
+}
+
+// SetDefaultBulkheadConfig 为未单独配置的名称启用隔离舱，只影响之后创建的隔离舱
+func SetDefaultBulkheadConfig(config BulkheadConfig) {
+	guardMutex.Lock()
+	defer guardMutex.Unlock()
+	defaultBulkheadConfig = &config
+}
+
+// SetBulkheadConfig 为指定名称的隔离舱设置配置，已创建的隔离舱会被替换
+func SetBulkheadConfig(name string, config BulkheadConfig) {
+	guardMutex.Lock()
+	defer guardMutex.Unlock()
+	bulkheadConfigs[name] = &config
+	delete(bulkheads, name)
+}
+
+// GetBreaker 按名称获取熔断器，不存在时创建，名称一般为依赖的类型与地址，如 http:example.com
+func GetBreaker(name string) *Breaker {
+	guardMutex.Lock()
+	defer guardMutex.Unlock()
+	if breaker, ok := breakers[name]; ok {
+		return breaker
+	}
+	config, ok := breakerConfigs[name]
+	if !ok {
+		config = &defaultBreakerConfig
+	}
+	breaker := NewBreaker(name, config)
+	breakers[name] = breaker
+	return breaker
+}
+
+// GetBulkhead 按名称获取隔离舱，隔离舱默认关闭，未配置时返回 nil 表示不限制并发
+// 突发流量下固定的并发上限会直接拒绝请求，需要时通过 SetBulkheadConfig 按调用方开启
+func GetBulkhead(name string) *Bulkhead {
+	guardMutex.Lock()
+	defer guardMutex.Unlock()
+	if bulkhead, ok := bulkheads[name]; ok {
+		return bulkhead
+	}
+	config, ok := bulkheadConfigs[name]
+	if !ok {
+		config = defaultBulkheadConfig
+	}
+	if config == nil {
+		return nil
+	}
+	bulkhead := NewBulkhead(name, config)
+	bulkheads[name] = bulkhead
+	return bulkhead
+}
+
+// Protect 在同名的隔离舱（已配置时）与熔断器保护下执行 f，可放在重试的每次尝试中
+// 熔断或并发已满时返回不可重试的错误，TryRetryWhenErr 会立即结束
+func Protect(ctx context.Context, name string, f func() error) error {
+	execute := func() error {
+		return GetBreaker(name).Execute(f)
+	}
+	bulkhead := GetBulkhead(name)
+	if bulkhead == nil {
+		return execute()
+	}
+	return bulkhead.Execute(ctx, execute)
+}
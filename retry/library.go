@@ -71,6 +71,7 @@ func TryRetryDynamicSleep(name string, maxCount int, f func(int) *time.Duration)
 	return metaerror.New("retry sleep failed, name:%s, max count:%d", name, maxCount)
 }
 
+// TryRetryWhenErr 遇到 metaerror.IsRetryable 为 false 的错误时立即返回
 func TryRetryWhenErr(name string, maxCount int, f func(int) error) error {
 	var err error
 	for i := 0; i < maxCount; i++ {
@@ -78,6 +79,9 @@ func TryRetryWhenErr(name string, maxCount int, f func(int) error) error {
 		if err == nil {
 			return nil
 		}
+		if !metaerror.IsRetryable(err) {
+			return err
+		}
 		slog.Info("wait retry when err",
 			"name", name,
 			"current index", i,
@@ -95,6 +99,9 @@ func TryRetryWhenErrSleep(name string, maxCount int, sleep time.Duration, f func
 		if err == nil {
 			return nil
 		}
+		if !metaerror.IsRetryable(err) {
+			return err
+		}
 		slog.Info("wait retry when err sleep",
 			"name", name,
 			"current index", i,
@@ -0,0 +1,18 @@
+package retry
+
+import metametrics "meta/meta-metrics"
+
+var (
+	breakerState = metametrics.NewGaugeVec(
+		"breaker_state", "Circuit breaker state: 0 closed, 1 open, 2 half-open.",
+		"name",
+	)
+	breakerRejected = metametrics.NewCounterVec(
+		"breaker_rejected_total", "Total number of calls rejected by open circuit breakers.",
+		"name",
+	)
+	bulkheadRejected = metametrics.NewCounterVec(
+		"bulkhead_rejected_total", "Total number of calls rejected by full bulkheads.",
+		"name",
+	)
+)
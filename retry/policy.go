@@ -0,0 +1,210 @@
+package retry
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	metaerror "meta/meta-error"
+	"time"
+)
+
+type Jitter string
+
+const (
+	JitterNone         Jitter = "none"
+	JitterFull         Jitter = "full"         // 在 [0, 指数退避) 内随机
+	JitterDecorrelated Jitter = "decorrelated" // 在 [初始等待, 上次等待 * 3) 内随机
+)
+
+// Policy 重试策略，零值字段使用默认值
+type Policy struct {
+	MaxAttempts  int           // 最多尝试次数，包括第一次，默认为 3，小于 0 时不限制，需配合 MaxElapsed
+	InitialDelay time.Duration // 第一次重试前的等待，默认为 100ms
+	MaxDelay     time.Duration // 单次等待上限，包括 DelayFor 指定的等待，默认为 30s
+	Multiplier   float64       // 每次等待的倍数，默认为 2，为 1 时固定等待
+	Jitter       Jitter        // 随机抖动方式，默认为 full
+	MaxElapsed   time.Duration // 从开始到放弃的最长时间，0 为不限制，等待会超出时直接放弃
+
+	// RetryIf 判断错误是否需要重试，默认为 metaerror.IsRetryable
+	RetryIf func(err error) bool
+	// DelayFor 返回错误指定的等待时间，如服务端的 Retry-After，ok 为 false 时使用退避时间
+	DelayFor func(err error) (delay time.Duration, ok bool)
+	// OnAttempt 每次尝试结束后调用
+	OnAttempt func(attempt *Attempt)
+}
+
+// Attempt 一次尝试的记录
+type Attempt struct {
+	Index    int
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+	Delay    time.Duration // 之后的等待时间，不再重试时为 0
+}
+
+// Result 重试的结果与每次尝试的记录
+type Result struct {
+	Attempts []*Attempt
+	Elapsed  time.Duration
+	Err      error
+}
+
+func (p *Policy) withDefault() Policy {
+	policy := Policy{}
+	if p != nil {
+		policy = *p
+	}
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = 3
+	}
+	if policy.InitialDelay <= 0 {
+		policy.InitialDelay = 100 * time.Millisecond
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = 30 * time.Second
+	}
+	if policy.MaxDelay < policy.InitialDelay {
+		policy.MaxDelay = policy.InitialDelay
+	}
+	if policy.Multiplier <= 0 {
+		policy.Multiplier = 2
+	}
+	if policy.Jitter == "" {
+		policy.Jitter = JitterFull
+	}
+	if policy.RetryIf == nil {
+		policy.RetryIf = metaerror.IsRetryable
+	}
+	return policy
+}
+
+// backoff 返回第 index 次重试前的等待，previous 为上次的等待
+func (p *Policy) backoff(index int, previous time.Duration) time.Duration {
+	switch p.Jitter {
+	case JitterDecorrelated:
+		if previous < p.InitialDelay {
+			previous = p.InitialDelay
+		}
+		upper := min(float64(previous)*3, float64(p.MaxDelay))
+		lower := float64(p.InitialDelay)
+		if upper <= lower {
+			return p.InitialDelay
+		}
+		return time.Duration(lower + rand.Float64()*(upper-lower))
+	default:
+		delay := min(float64(p.InitialDelay)*math.Pow(p.Multiplier, float64(index)), float64(p.MaxDelay))
+		if p.Jitter == JitterFull {
+			delay = rand.Float64() * delay
+		}
+		return time.Duration(delay)
+	}
+}
+
+// Do 按策略执行 f 直到成功、遇到不可重试的错误、次数或时间用尽、或 ctx 结束
+// ctx 结束时立即停止等待并返回 ctx 的错误，最后一次尝试的错误见 Attempts
+func Do(ctx context.Context, name string, policy *Policy, f func(ctx context.Context, attempt int) error) *Result {
+	p := policy.withDefault()
+	result := &Result{}
+	start := time.Now()
+	var delay time.Duration
+	for index := 0; p.MaxAttempts < 0 || index < p.MaxAttempts; index++ {
+		if err := ctx.Err(); err != nil {
+			result.Err = wrapContextErr(err, name, result.Err)
+			break
+		}
+		attempt := &Attempt{Index: index, Start: time.Now()}
+		attempt.Err = f(ctx, index)
+		attempt.Duration = time.Since(attempt.Start)
+		result.Attempts = append(result.Attempts, attempt)
+		result.Err = attempt.Err
+		if attempt.Err == nil || !p.RetryIf(attempt.Err) || (p.MaxAttempts > 0 && index+1 >= p.MaxAttempts) {
+			p.onAttempt(attempt)
+			break
+		}
+		delay = p.backoff(index, delay)
+		if p.DelayFor != nil {
+			// 服务端返回的 Retry-After 同样受 MaxDelay 限制，避免一次等待过久
+			if specified, ok := p.DelayFor(attempt.Err); ok {
+				delay = min(specified, p.MaxDelay)
+			}
+		}
+		if p.MaxElapsed > 0 && time.Since(start)+delay > p.MaxElapsed {
+			p.onAttempt(attempt)
+			break
+		}
+		attempt.Delay = delay
+		p.onAttempt(attempt)
+		slog.InfoContext(
+			ctx, "wait retry policy",
+			"name", name,
+			"current index", index,
+			"max count", p.MaxAttempts,
+			"delay", delay,
+			"err", attempt.Err,
+		)
+		if err := sleepContext(ctx, delay); err != nil {
+			result.Err = wrapContextErr(err, name, result.Err)
+			break
+		}
+	}
+	result.Elapsed = time.Since(start)
+	return result
+}
+
+// DoValue 与 Do 相同，返回最后一次尝试的值
+func DoValue[T any](
+	ctx context.Context,
+	name string,
+	policy *Policy,
+	f func(ctx context.Context, attempt int) (T, error),
+) (T, *Result) {
+	var value T
+	result := Do(
+		ctx, name, policy, func(ctx context.Context, attempt int) error {
+			var err error
+			value, err = f(ctx, attempt)
+			return err
+		},
+	)
+	return value, result
+}
+
+func (p *Policy) onAttempt(attempt *Attempt) {
+	if p.OnAttempt != nil {
+		p.OnAttempt(attempt)
+	}
+}
+
+func wrapContextErr(err error, name string, lastErr error) error {
+	if lastErr == nil {
+		return metaerror.Wrap(err, "retry canceled, name:%s", name)
+	}
+	return metaerror.Wrap(err, "retry canceled, name:%s, last error:%v", name, lastErr)
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RetryIfAny 任一条件满足即重试
+func RetryIfAny(predicates ...func(err error) bool) func(err error) bool {
+	return func(err error) bool {
+		for _, predicate := range predicates {
+			if predicate(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
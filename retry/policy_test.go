@@ -0,0 +1,114 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	metaerror "meta/meta-error"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPolicyBackoff(t *testing.T) {
+	policy := (&Policy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, Jitter: JitterNone}).withDefault()
+	expected := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, delay := range expected {
+		if actual := policy.backoff(i, 0); actual != delay*time.Millisecond {
+			t.Errorf("backoff(%d) = %s, want %s", i, actual, delay*time.Millisecond)
+		}
+	}
+	policy.Jitter = JitterFull
+	for i := 0; i < 100; i++ {
+		if delay := policy.backoff(3, 0); delay < 0 || delay >= 800*time.Millisecond {
+			t.Fatalf("full jitter out of range: %s", delay)
+		}
+	}
+	policy.Jitter = JitterDecorrelated
+	previous := time.Duration(0)
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(i, previous)
+		if delay < policy.InitialDelay || delay > policy.MaxDelay {
+			t.Fatalf("decorrelated jitter out of range: %s", delay)
+		}
+		previous = delay
+	}
+}
+
+func TestDo(t *testing.T) {
+	failure := errors.New("failure")
+	var hooked []int
+	result := Do(
+		context.Background(), "test", &Policy{
+			MaxAttempts: 5, InitialDelay: time.Millisecond,
+			OnAttempt: func(attempt *Attempt) {
+				hooked = append(hooked, attempt.Index)
+			},
+		}, func(ctx context.Context, attempt int) error {
+			if attempt < 2 {
+				return failure
+			}
+			return nil
+		},
+	)
+	if result.Err != nil || len(result.Attempts) != 3 || len(hooked) != 3 {
+		t.Fatalf("should succeed on third attempt: %+v", result)
+	}
+	if !errors.Is(result.Attempts[0].Err, failure) || result.Attempts[0].Delay <= 0 || result.Attempts[2].Delay != 0 {
+		t.Errorf("attempt history should keep errors: %+v", result.Attempts[0])
+	}
+
+	result = Do(
+		context.Background(), "test", nil, func(ctx context.Context, attempt int) error {
+			return metaerror.ErrNotFound
+		},
+	)
+	if len(result.Attempts) != 1 || !errors.Is(result.Err, metaerror.ErrNotFound) {
+		t.Errorf("non-retryable error should stop: %+v", result)
+	}
+
+	result = Do(
+		context.Background(), "test", &Policy{
+			MaxAttempts: -1, InitialDelay: 20 * time.Millisecond, Jitter: JitterNone, MaxElapsed: 50 * time.Millisecond,
+		}, func(ctx context.Context, attempt int) error {
+			return failure
+		},
+	)
+	if len(result.Attempts) != 2 || !errors.Is(result.Err, failure) {
+		t.Errorf("max elapsed should stop before exceeding: %d attempts", len(result.Attempts))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result = Do(
+		ctx, "test", &Policy{MaxAttempts: 5, InitialDelay: time.Minute}, func(ctx context.Context, attempt int) error {
+			return NewStatusError(http.StatusServiceUnavailable, http.Header{"Retry-After": {"1"}})
+		},
+	)
+	if time.Since(start) > time.Second || !errors.Is(result.Err, context.DeadlineExceeded) {
+		t.Errorf("canceled context should stop waiting: %v", result.Err)
+	}
+}
+
+func TestDoClampDelayFor(t *testing.T) {
+	result := Do(
+		context.Background(), "test", &Policy{
+			MaxAttempts: 2, InitialDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, DelayFor: DelayForRetryAfter,
+		}, func(ctx context.Context, attempt int) error {
+			return NewStatusError(http.StatusServiceUnavailable, http.Header{"Retry-After": {"3600"}})
+		},
+	)
+	if delay := result.Attempts[0].Delay; delay != 10*time.Millisecond {
+		t.Errorf("retry after should be clamped to max delay: %s", delay)
+	}
+}
+
+func TestRetryOnStatus(t *testing.T) {
+	err := metaerror.Wrap(NewStatusError(http.StatusTooManyRequests, http.Header{"Retry-After": {"3"}}))
+	if !RetryOnStatus()(err) || RetryOnStatus()(NewStatusError(http.StatusNotFound, nil)) {
+		t.Error("unexpected status retry decision")
+	}
+	if delay, ok := DelayForRetryAfter(err); !ok || delay != 3*time.Second {
+		t.Errorf("unexpected retry after: %s", delay)
+	}
+}
@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryableStatuses 默认可以重试的 HTTP 状态码
+var RetryableStatuses = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// StatusError 非预期的 HTTP 状态码
+type StatusError struct {
+	Status     int
+	RetryAfter time.Duration // 响应中的 Retry-After，没有时为 0
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code:%d", e.Status)
+}
+
+// NewStatusError 按响应头解析 Retry-After，支持秒数与 HTTP 日期
+func NewStatusError(status int, header http.Header) *StatusError {
+	statusError := &StatusError{Status: status}
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			statusError.RetryAfter = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(value); err == nil {
+			statusError.RetryAfter = max(time.Until(date), 0)
+		}
+	}
+	return statusError
+}
+
+// RetryOnStatus 错误为 StatusError 且状态码在 statuses 中时重试，statuses 为空时使用 RetryableStatuses
+func RetryOnStatus(statuses ...int) func(err error) bool {
+	if len(statuses) == 0 {
+		statuses = RetryableStatuses
+	}
+	return func(err error) bool {
+		var statusError *StatusError
+		return errors.As(err, &statusError) && slices.Contains(statuses, statusError.Status)
+	}
+}
+
+// DelayForRetryAfter 使用响应中的 Retry-After 作为等待时间
+func DelayForRetryAfter(err error) (time.Duration, bool) {
+	var statusError *StatusError
+	if errors.As(err, &statusError) && statusError.RetryAfter > 0 {
+		return statusError.RetryAfter, true
+	}
+	return 0, false
+}
@@ -0,0 +1,173 @@
+package rpc
+
+import (
+	"log/slog"
+	"meta/engine"
+	metaerror "meta/meta-error"
+	"meta/registry"
+	"meta/subsystem"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ClientSubsystem 按名称管理到其他服务的 gRPC 连接
+type ClientSubsystem struct {
+	subsystem.Subsystem
+	GetConfig func() map[string]*ClientConfig
+
+	GetDialOptions     func(name string) []grpc.DialOption // 额外的连接参数，默认使用明文传输
+	UnaryInterceptors  []grpc.UnaryClientInterceptor       // 追加在内置拦截器之后
+	StreamInterceptors []grpc.StreamClientInterceptor      // 追加在内置拦截器之后
+
+	mutex   sync.RWMutex
+	clients map[string]*client
+}
+
+type client struct {
+	conn        *grpc.ClientConn
+	resolver    *staticResolver
+	cancelWatch func()
+}
+
+func GetClientSubsystem() *ClientSubsystem {
+	if thisSubsystem := engine.GetSubsystem[*ClientSubsystem](); thisSubsystem != nil {
+		return thisSubsystem.(*ClientSubsystem)
+	}
+	return nil
+}
+
+func (s *ClientSubsystem) GetName() string {
+	return "RpcClient"
+}
+
+func (s *ClientSubsystem) Start() error {
+	var configs map[string]*ClientConfig
+	if s.GetConfig != nil {
+		configs = s.GetConfig()
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.clients = make(map[string]*client)
+	for name, config := range configs {
+		if config == nil {
+			_ = s.closeClientsUnsafe()
+			return metaerror.New("rpc client config is nil, name:%s", name)
+		}
+		c, err := s.newClient(name, config)
+		if err != nil {
+			// 关闭已创建的连接，避免泄漏
+			_ = s.closeClientsUnsafe()
+			return err
+		}
+		s.clients[name] = c
+		slog.Info("Rpc client created", "name", name, "addresses", config.Addresses, "module", config.Module)
+	}
+	return nil
+}
+
+func (s *ClientSubsystem) Stop() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.closeClientsUnsafe()
+}
+
+func (s *ClientSubsystem) closeClientsUnsafe() error {
+	var finalErr error
+	for name, c := range s.clients {
+		if c.cancelWatch != nil {
+			c.cancelWatch()
+		}
+		if err := c.conn.Close(); err != nil {
+			finalErr = metaerror.Join(finalErr, metaerror.Wrap(err, "close rpc client failed, name:%s", name))
+		}
+	}
+	s.clients = nil
+	return finalErr
+}
+
+// GetConn 返回指定名称的连接，不存在时返回 nil
+func (s *ClientSubsystem) GetConn(name string) *grpc.ClientConn {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if c, ok := s.clients[name]; ok {
+		return c.conn
+	}
+	return nil
+}
+
+// UpdateAddresses 替换指定连接的地址列表，用于服务发现等动态场景
+func (s *ClientSubsystem) UpdateAddresses(name string, addresses []string) error {
+	s.mutex.RLock()
+	c, ok := s.clients[name]
+	s.mutex.RUnlock()
+	if !ok {
+		return metaerror.New("rpc client not found, name:%s", name)
+	}
+	return c.resolver.UpdateAddresses(addresses)
+}
+
+func (s *ClientSubsystem) newClient(name string, config *ClientConfig) (*client, error) {
+	serviceConfig, err := config.getServiceConfig()
+	if err != nil {
+		return nil, metaerror.Wrap(err, "build service config failed, name:%s", name)
+	}
+	r := newStaticResolver(config.Addresses)
+
+	unaryInterceptors := append(
+		[]grpc.UnaryClientInterceptor{TracingUnaryClientInterceptor(), PropagationUnaryClientInterceptor()},
+		s.UnaryInterceptors...,
+	)
+	streamInterceptors := append(
+		[]grpc.StreamClientInterceptor{TracingStreamClientInterceptor(), PropagationStreamClientInterceptor()},
+		s.StreamInterceptors...,
+	)
+	options := []grpc.DialOption{
+		grpc.WithResolvers(r),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+		grpc.WithChainStreamInterceptor(streamInterceptors...),
+	}
+	var extraOptions []grpc.DialOption
+	if s.GetDialOptions != nil {
+		extraOptions = s.GetDialOptions(name)
+	}
+	if len(extraOptions) == 0 {
+		options = append(options, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		options = append(options, extraOptions...)
+	}
+	conn, err := grpc.NewClient(r.Scheme()+":///"+name, options...)
+	if err != nil {
+		return nil, metaerror.Wrap(err, "create rpc client failed, name:%s", name)
+	}
+	c := &client{conn: conn, resolver: r}
+	if config.Module != "" {
+		registrySubsystem := registry.GetSubsystem()
+		if registrySubsystem == nil {
+			_ = conn.Close()
+			return nil, metaerror.New("registry subsystem not found, name:%s, module:%s", name, config.Module)
+		}
+		cancelWatch, err := registrySubsystem.Watch(
+			config.Module, func(nodes []*registry.Node) {
+				var addresses []string
+				for _, node := range nodes {
+					if address := node.GetRpcAddress(); address != "" {
+						addresses = append(addresses, address)
+					}
+				}
+				slog.Info("Rpc client addresses changed", "name", name, "module", config.Module, "addresses", addresses)
+				if err := r.UpdateAddresses(addresses); err != nil {
+					slog.Error("Rpc client update addresses failed", "name", name, "err", err)
+				}
+			},
+		)
+		if err != nil {
+			_ = conn.Close()
+			return nil, metaerror.Wrap(err, "watch rpc client module failed, name:%s", name)
+		}
+		c.cancelWatch = cancelWatch
+	}
+	return c, nil
+}
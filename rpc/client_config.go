@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+type ClientConfig struct {
+	Addresses []string           `yaml:"addresses"` // 静态地址列表，按轮询负载均衡
+	Module    string             `yaml:"module"`    // 通过服务发现解析的模块名，设置后忽略 Addresses
+	Timeout   time.Duration      `yaml:"timeout"`   // 单次调用超时，0 为不限制
+	Retry     *ClientRetryConfig `yaml:"retry"`     // 重试策略，为空则不重试
+}
+
+type ClientRetryConfig struct {
+	MaxAttempts       int           `yaml:"max-attempts"`       // 最大尝试次数（含首次），gRPC 最多支持 5 次
+	InitialBackoff    time.Duration `yaml:"initial-backoff"`    // 首次重试退避
+	MaxBackoff        time.Duration `yaml:"max-backoff"`        // 最大退避
+	BackoffMultiplier float64       `yaml:"backoff-multiplier"` // 退避倍数
+	RetryableCodes    []string      `yaml:"retryable-codes"`    // 可重试的状态码，如 UNAVAILABLE
+}
+
+type serviceConfig struct {
+	LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig"`
+	MethodConfig        []methodConfig        `json:"methodConfig,omitempty"`
+}
+
+type methodConfig struct {
+	Name        []struct{}   `json:"name"`
+	Timeout     string       `json:"timeout,omitempty"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+// getServiceConfig 生成 gRPC 服务配置，包含轮询负载均衡、超时与重试
+func (c *ClientConfig) getServiceConfig() (string, error) {
+	config := serviceConfig{
+		LoadBalancingConfig: []map[string]struct{}{{"round_robin": {}}},
+	}
+	method := methodConfig{Name: []struct{}{{}}}
+	if c.Timeout > 0 {
+		method.Timeout = formatDuration(c.Timeout)
+	}
+	if c.Retry != nil && c.Retry.MaxAttempts > 1 {
+		policy := &retryPolicy{
+			MaxAttempts:          c.Retry.MaxAttempts,
+			InitialBackoff:       formatDuration(c.Retry.InitialBackoff),
+			MaxBackoff:           formatDuration(c.Retry.MaxBackoff),
+			BackoffMultiplier:    c.Retry.BackoffMultiplier,
+			RetryableStatusCodes: c.Retry.RetryableCodes,
+		}
+		if c.Retry.InitialBackoff <= 0 {
+			policy.InitialBackoff = formatDuration(100 * time.Millisecond)
+		}
+		if c.Retry.MaxBackoff <= 0 {
+			policy.MaxBackoff = formatDuration(time.Second)
+		}
+		if policy.BackoffMultiplier <= 0 {
+			policy.BackoffMultiplier = 2
+		}
+		if len(policy.RetryableStatusCodes) == 0 {
+			policy.RetryableStatusCodes = []string{"UNAVAILABLE"}
+		}
+		method.RetryPolicy = policy
+	}
+	if method.Timeout != "" || method.RetryPolicy != nil {
+		config.MethodConfig = append(config.MethodConfig, method)
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func formatDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
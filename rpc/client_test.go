@@ -0,0 +1,139 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func startHealthServer(t *testing.T, service string) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return listener.Addr().String(), server.Stop
+}
+
+func checkHealth(conn *grpc.ClientConn, service string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	return err
+}
+
+func TestServiceConfig(t *testing.T) {
+	config := &ClientConfig{Timeout: 1500 * time.Millisecond, Retry: &ClientRetryConfig{MaxAttempts: 3}}
+	serviceConfig, err := config.getServiceConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(serviceConfig), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	method := decoded["methodConfig"].([]any)[0].(map[string]any)
+	if method["timeout"] != "1.5s" {
+		t.Errorf("timeout = %v", method["timeout"])
+	}
+	policy := method["retryPolicy"].(map[string]any)
+	if policy["initialBackoff"] != "0.1s" || policy["backoffMultiplier"] != 2.0 {
+		t.Errorf("retry defaults not applied: %v", policy)
+	}
+	// 由 gRPC 自身校验生成的配置
+	conn, err := grpc.NewClient(
+		"passthrough:///127.0.0.1:1",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+
+	serviceConfig, err = (&ClientConfig{}).getServiceConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serviceConfig != `{"loadBalancingConfig":[{"round_robin":{}}]}` {
+		t.Errorf("service config = %s", serviceConfig)
+	}
+}
+
+func TestClientUpdateAddresses(t *testing.T) {
+	addressA, stopA := startHealthServer(t, "a")
+	defer stopA()
+	addressB, stopB := startHealthServer(t, "b")
+	defer stopB()
+
+	s := &ClientSubsystem{
+		GetConfig: func() map[string]*ClientConfig {
+			return map[string]*ClientConfig{"test": {Addresses: []string{addressA}}}
+		},
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = s.Stop()
+	}()
+	conn := s.GetConn("test")
+	if conn == nil {
+		t.Fatal("conn not found")
+	}
+	if err := checkHealth(conn, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateAddresses("test", []string{addressB}); err != nil {
+		t.Fatal(err)
+	}
+	stopA()
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		err := checkHealth(conn, "b")
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err := s.UpdateAddresses("missing", nil); err == nil {
+		t.Error("update missing client should fail")
+	}
+}
+
+func TestClientStartError(t *testing.T) {
+	s := &ClientSubsystem{
+		GetConfig: func() map[string]*ClientConfig {
+			return map[string]*ClientConfig{
+				"ok":  {Addresses: []string{"127.0.0.1:1"}},
+				"bad": {Addresses: []string{"127.0.0.1:1"}, Retry: &ClientRetryConfig{MaxAttempts: 2, RetryableCodes: []string{"NOT_A_CODE"}}},
+			}
+		},
+	}
+	if err := s.Start(); err == nil {
+		t.Fatal("invalid retry code should fail")
+	}
+	if len(s.clients) != 0 {
+		t.Errorf("clients not closed on error: %d", len(s.clients))
+	}
+
+	if err := (&ClientSubsystem{}).Start(); err != nil {
+		t.Errorf("nil GetConfig should start without clients: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	metapanic "meta/meta-panic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryUnaryInterceptor 捕获处理函数中的 panic，返回 Internal
+func RecoveryUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				metapanic.ProcessPanic("grpc panic", r, "method: %s\npeer: %s", info.FullMethod, getPeerAddr(ctx))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func RecoveryStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				metapanic.ProcessPanic("grpc panic", r, "method: %s\npeer: %s", info.FullMethod, getPeerAddr(ss.Context()))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// LoggerUnaryInterceptor 记录每次调用的方法、状态码与耗时
+func LoggerUnaryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, getLogger(logger), info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func LoggerStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(ss.Context(), getLogger(logger), info.FullMethod, start, err)
+		return err
+	}
+}
+
+// ErrorCodeUnaryInterceptor 将 metaerror 错误转换为 gRPC 状态，并交给 metapanic 处理
+func ErrorCodeUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, processError(ctx, info.FullMethod, err)
+		}
+		return resp, nil
+	}
+}
+
+func ErrorCodeStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		err := handler(srv, ss)
+		if err != nil {
+			return processError(ss.Context(), info.FullMethod, err)
+		}
+		return nil
+	}
+}
+
+func processError(ctx context.Context, method string, err error) error {
+	if _, ok := status.FromError(err); !ok {
+		metapanic.ProcessError(err, "grpc error\nmethod: %s\npeer: %s", method, getPeerAddr(ctx))
+	}
+	return ToStatus(err).Err()
+}
+
+func logCall(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []any{
+		slog.String("method", method),
+		slog.String("peer", getPeerAddr(ctx)),
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("err", err.Error()))
+	}
+	if code == codes.Internal || code == codes.Unknown {
+		logger.ErrorContext(ctx, "Grpc", attrs...)
+	} else {
+		logger.InfoContext(ctx, "Grpc", attrs...)
+	}
+}
+
+func getLogger(logger *slog.Logger) *slog.Logger {
+	if logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+func getPeerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"context"
+	metatrace "meta/meta-trace"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	metadataRequestId   = strings.ToLower(metatrace.HeaderRequestId)
+	metadataTraceParent = strings.ToLower(metatrace.HeaderTraceParent)
+)
+
+// PropagationUnaryInterceptor 从请求元数据中读取请求ID与 traceparent 写入 context
+func PropagationUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		return handler(extractIncoming(ctx), req)
+	}
+}
+
+func PropagationStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: extractIncoming(ss.Context())})
+	}
+}
+
+// PropagationUnaryClientInterceptor 将 context 中的请求ID与 traceparent 写入请求元数据
+func PropagationUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return invoker(injectOutgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func PropagationStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		return streamer(injectOutgoing(ctx), desc, cc, method, opts...)
+	}
+}
+
+func extractIncoming(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return metatrace.Extract(
+		ctx, func(key string) string {
+			if values := md.Get(key); len(values) > 0 {
+				return values[0]
+			}
+			return ""
+		},
+	)
+}
+
+func injectOutgoing(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if requestId := metatrace.GetRequestId(ctx); requestId != "" && len(md.Get(metadataRequestId)) == 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, metadataRequestId, requestId)
+	}
+	if traceParent := metatrace.GetTraceParent(ctx); traceParent != "" && len(md.Get(metadataTraceParent)) == 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, metadataTraceParent, traceParent)
+	}
+	return ctx
+}
+
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"sync"
+
+	"google.golang.org/grpc/resolver"
+)
+
+const resolverScheme = "meta"
+
+// staticResolver 使用静态地址列表的解析器，地址可在运行时替换
+type staticResolver struct {
+	mutex     sync.Mutex
+	addresses []string
+	cc        resolver.ClientConn
+}
+
+func newStaticResolver(addresses []string) *staticResolver {
+	return &staticResolver{addresses: addresses}
+}
+
+func (r *staticResolver) Build(
+	target resolver.Target,
+	cc resolver.ClientConn,
+	opts resolver.BuildOptions,
+) (resolver.Resolver, error) {
+	r.mutex.Lock()
+	r.cc = cc
+	state := r.getStateUnsafe()
+	r.mutex.Unlock()
+	if err := cc.UpdateState(state); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *staticResolver) Scheme() string {
+	return resolverScheme
+}
+
+func (r *staticResolver) ResolveNow(resolver.ResolveNowOptions) {
+}
+
+func (r *staticResolver) Close() {
+	r.mutex.Lock()
+	r.cc = nil
+	r.mutex.Unlock()
+}
+
+func (r *staticResolver) UpdateAddresses(addresses []string) error {
+	r.mutex.Lock()
+	r.addresses = addresses
+	cc := r.cc
+	state := r.getStateUnsafe()
+	r.mutex.Unlock()
+	if cc == nil {
+		// 尚未建立连接或已进入空闲，下次 Build 时使用新地址
+		return nil
+	}
+	return cc.UpdateState(state)
+}
+
+func (r *staticResolver) getStateUnsafe() resolver.State {
+	state := resolver.State{}
+	for _, address := range r.addresses {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: address})
+	}
+	return state
+}
@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	metaerrorcode "meta/error-code"
+	metaerror "meta/meta-error"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorInfoReason 携带 metaerror 错误码的 ErrorInfo 原因标识
+const ErrorInfoReason = "META_ERROR"
+
+// ErrorInfoCodeKey ErrorInfo.Metadata 中存放错误码的键
+const ErrorInfoCodeKey = "code"
+
+var (
+	grpcCodeMutex sync.RWMutex
+	grpcCodeMap   = map[int]codes.Code{
+		int(metaerrorcode.Success):         codes.OK,
+		int(metaerrorcode.CommonError):     codes.Unknown,
+		int(metaerrorcode.PanicError):      codes.Internal,
+		int(metaerrorcode.UnknownError):    codes.Unknown,
+		int(metaerrorcode.TooManyRequests): codes.ResourceExhausted,
+		int(metaerrorcode.ParamError):      codes.InvalidArgument,
+		http.StatusBadRequest:              codes.InvalidArgument,
+		http.StatusUnauthorized:            codes.Unauthenticated,
+		http.StatusForbidden:               codes.PermissionDenied,
+		http.StatusNotFound:                codes.NotFound,
+		http.StatusConflict:                codes.AlreadyExists,
+		http.StatusTooManyRequests:         codes.ResourceExhausted,
+		http.StatusServiceUnavailable:      codes.Unavailable,
+	}
+)
+
+// RegisterGrpcCode 注册业务错误码对应的 gRPC 状态码
+func RegisterGrpcCode[T metaerrorcode.Numeric](code T, grpcCode codes.Code) {
+	grpcCodeMutex.Lock()
+	defer grpcCodeMutex.Unlock()
+	grpcCodeMap[int(code)] = grpcCode
+}
+
+func GetGrpcCode[T metaerrorcode.Numeric](code T) codes.Code {
+	grpcCodeMutex.RLock()
+	defer grpcCodeMutex.RUnlock()
+	if grpcCode, ok := grpcCodeMap[int(code)]; ok {
+		return grpcCode
+	}
+	return codes.Unknown
+}
+
+// ToStatus 将错误转换为 gRPC 状态，metaerror 的错误码通过 ErrorInfo 传递
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.New(codes.DeadlineExceeded, context.DeadlineExceeded.Error())
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.New(codes.Canceled, context.Canceled.Error())
+	}
+	code := metaerror.GetErrorCodeFromError(err)
+	s := status.New(GetGrpcCode(code), getStatusMessage(code, err))
+	detailStatus, detailErr := s.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason:   ErrorInfoReason,
+			Metadata: map[string]string{ErrorInfoCodeKey: strconv.Itoa(code)},
+		},
+	)
+	if detailErr != nil {
+		return s
+	}
+	return detailStatus
+}
+
+// getStatusMessage 只返回用户提示或错误码对应的提示，错误详情只记录在服务端
+func getStatusMessage(code int, err error) string {
+	if message := metaerror.GetUserMessage(err); message != "" {
+		return message
+	}
+	if message := metaerrorcode.GetMessage(code); message != "" {
+		return message
+	}
+	return GetGrpcCode(code).String()
+}
+
+// GetErrorCodeFromStatus 从 gRPC 状态中取回 metaerror 错误码
+func GetErrorCodeFromStatus(s *status.Status) (int, bool) {
+	for _, detail := range s.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.Reason != ErrorInfoReason {
+			continue
+		}
+		code, err := strconv.Atoi(info.Metadata[ErrorInfoCodeKey])
+		if err != nil {
+			return 0, false
+		}
+		return code, true
+	}
+	return 0, false
+}
+
+// GetErrorCodeFromError 从 gRPC 调用返回的错误中取回 metaerror 错误码
+func GetErrorCodeFromError(err error) int {
+	s, ok := status.FromError(err)
+	if !ok {
+		return metaerror.GetErrorCodeFromError(err)
+	}
+	if code, ok := GetErrorCodeFromStatus(s); ok {
+		return code
+	}
+	if s.Code() == codes.OK {
+		return int(metaerrorcode.Success)
+	}
+	return int(metaerrorcode.UnknownError)
+}
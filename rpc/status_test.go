@@ -0,0 +1,30 @@
+package rpc
+
+import (
+	"errors"
+	metaerror "meta/meta-error"
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestToStatus(t *testing.T) {
+	err := metaerror.WrapCode(errors.New("dial tcp 10.0.0.1:3306: connection refused"), http.StatusNotFound, "load user")
+	s := ToStatus(err)
+	if s.Code() != codes.NotFound {
+		t.Errorf("code = %v, want NotFound", s.Code())
+	}
+	if strings.Contains(s.Message(), "10.0.0.1") || strings.Contains(s.Message(), "load user") {
+		t.Errorf("internal detail leaked: %q", s.Message())
+	}
+	if code, ok := GetErrorCodeFromStatus(s); !ok || code != http.StatusNotFound {
+		t.Errorf("error code = %d, %v", code, ok)
+	}
+
+	s = ToStatus(metaerror.WithUserMessage(err, "user not found"))
+	if s.Message() != "user not found" {
+		t.Errorf("message = %q, want user message", s.Message())
+	}
+}
@@ -5,16 +5,29 @@ import (
 	"log/slog"
 	"meta/engine"
 	metaerror "meta/meta-error"
+	metalog "meta/meta-log"
 	"meta/metaroutine"
 	"meta/subsystem"
 	"net"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 type Subsystem struct {
 	subsystem.Subsystem
 	GetPort func() int32
+
+	RegisterService    func(server *grpc.Server)      // 注册服务
+	GetServerOptions   func() []grpc.ServerOption     // 额外的服务器参数
+	UnaryInterceptors  []grpc.UnaryServerInterceptor  // 追加在内置拦截器之后
+	StreamInterceptors []grpc.StreamServerInterceptor // 追加在内置拦截器之后
+	EnableReflection   bool                           // 是否注册反射服务
+
+	server       *grpc.Server
+	healthServer *health.Server
 }
 
 func GetSubsystem() *Subsystem {
@@ -29,35 +42,87 @@ func (s *Subsystem) GetName() string {
 }
 
 func (s *Subsystem) Start() error {
-	if s.GetPort != nil {
-		metaroutine.SafeGoWithRestart(
-			"Rpc start",
-			s.startSubsystem,
-		)
+	if s.GetPort == nil {
+		return nil
 	}
-	return nil
-}
-
-func (s *Subsystem) startSubsystem() error {
 	port := s.GetPort()
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		return metaerror.Wrap(err, "failed to listen, port:%d", port)
 	}
-	defer func(lis net.Listener) {
-		err := lis.Close()
-		if err != nil {
-			slog.Error("Error closing listener", "err", err)
-		}
-	}(lis)
-
-	slog.Info("Rpc server is listening", "port", port)
 
-	grpcServer := grpc.NewServer()
-	err = grpcServer.Serve(lis)
-	if err != nil {
-		return err
+	s.server = grpc.NewServer(s.getServerOptions()...)
+	s.healthServer = health.NewServer()
+	healthpb.RegisterHealthServer(s.server, s.healthServer)
+	if s.EnableReflection {
+		reflection.Register(s.server)
 	}
+	if s.RegisterService != nil {
+		s.RegisterService(s.server)
+	}
+
+	slog.Info("Rpc server is listening", "port", port, "services", len(s.server.GetServiceInfo()))
 
+	metaroutine.SafeGo(
+		"Rpc serve",
+		func() error {
+			return s.server.Serve(lis)
+		},
+	)
+	return nil
+}
+
+func (s *Subsystem) Stop() error {
+	if s.healthServer != nil {
+		s.healthServer.Shutdown()
+	}
+	if s.server != nil {
+		s.server.GracefulStop()
+	}
 	return nil
 }
+
+// GetServer 返回 gRPC 服务器，仅在 Start 之后有效
+func (s *Subsystem) GetServer() *grpc.Server {
+	return s.server
+}
+
+// SetServingStatus 设置健康检查中指定服务的状态，service 为空表示整体状态
+func (s *Subsystem) SetServingStatus(service string, serving bool) {
+	if s.healthServer == nil {
+		return
+	}
+	servingStatus := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		servingStatus = healthpb.HealthCheckResponse_SERVING
+	}
+	s.healthServer.SetServingStatus(service, servingStatus)
+}
+
+func (s *Subsystem) getServerOptions() []grpc.ServerOption {
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		PropagationUnaryInterceptor(),
+		TracingUnaryInterceptor(),
+		LoggerUnaryInterceptor(metalog.GetLogger()),
+		RecoveryUnaryInterceptor(),
+		ErrorCodeUnaryInterceptor(),
+	}
+	unaryInterceptors = append(unaryInterceptors, s.UnaryInterceptors...)
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		PropagationStreamInterceptor(),
+		TracingStreamInterceptor(),
+		LoggerStreamInterceptor(metalog.GetLogger()),
+		RecoveryStreamInterceptor(),
+		ErrorCodeStreamInterceptor(),
+	}
+	streamInterceptors = append(streamInterceptors, s.StreamInterceptors...)
+
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+	if s.GetServerOptions != nil {
+		options = append(options, s.GetServerOptions()...)
+	}
+	return options
+}
@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"context"
+	metatracing "meta/meta-tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// TracingUnaryInterceptor 记录服务端 span，需位于 PropagationUnaryInterceptor 之后
+func TracingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx, span := metatracing.StartSpan(ctx, info.FullMethod, trace.SpanKindServer, getRpcAttributes(info.FullMethod)...)
+		resp, err := handler(ctx, req)
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", status.Code(err).String()))
+		metatracing.EndSpan(span, err)
+		return resp, err
+	}
+}
+
+func TracingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, span := metatracing.StartSpan(
+			ss.Context(), info.FullMethod, trace.SpanKindServer, getRpcAttributes(info.FullMethod)...,
+		)
+		err := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", status.Code(err).String()))
+		metatracing.EndSpan(span, err)
+		return err
+	}
+}
+
+// TracingUnaryClientInterceptor 记录客户端 span，需位于 PropagationUnaryClientInterceptor 之前
+func TracingUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		ctx, span := metatracing.StartSpan(ctx, method, trace.SpanKindClient, getRpcAttributes(method)...)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", status.Code(err).String()))
+		metatracing.EndSpan(span, err)
+		return err
+	}
+}
+
+// TracingStreamClientInterceptor span 在流建立后结束，不覆盖整个流的生命周期
+func TracingStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		ctx, span := metatracing.StartSpan(ctx, method, trace.SpanKindClient, getRpcAttributes(method)...)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		metatracing.EndSpan(span, err)
+		return stream, err
+	}
+}
+
+func getRpcAttributes(fullMethod string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.method", fullMethod),
+	}
+}
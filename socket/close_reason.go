@@ -0,0 +1,12 @@
+package socket
+
+// CloseMessageId 服务器主动断开前发送给客户端的消息ID，ProtoData 为断开原因文本
+const CloseMessageId int32 = -1
+
+type CloseReason string
+
+const (
+	CloseReasonServerFull       CloseReason = "server connection limit reached"
+	CloseReasonIpLimit          CloseReason = "too many connections from ip"
+	CloseReasonMessageRateLimit CloseReason = "message rate limit exceeded"
+)
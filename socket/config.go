@@ -0,0 +1,11 @@
+package socket
+
+import "time"
+
+type Config struct {
+	MaxConnections      int           `yaml:"max-connections"`        // 最大连接数，0 为不限制
+	MaxConnectionsPerIp int           `yaml:"max-connections-per-ip"` // 单个 IP 最大连接数，0 为不限制
+	MessageRate         float64       `yaml:"message-rate"`           // 单个连接每秒允许接收的消息数，0 为不限制
+	MessageBurst        int           `yaml:"message-burst"`          // 单个连接允许的消息突发数
+	AcceptBackoffMax    time.Duration `yaml:"accept-backoff-max"`     // Accept 出错时的最大退避时间
+}
@@ -4,9 +4,9 @@ import (
 	"fmt"
 	googleProto "github.com/golang/protobuf/proto"
 	"golang.org/x/exp/constraints"
-	"log/slog"
 	metaerror "meta/meta-error"
 	"net"
+	"strconv"
 )
 
 func Connect(host string, port int32) (int32, error) {
@@ -15,7 +15,7 @@ func Connect(host string, port int32) (int32, error) {
 		return -1, metaerror.New("socket subsystem not found")
 	}
 
-	conn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", host, port))
+	conn, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
 	if err != nil {
 		return -1, metaerror.Wrap(err, "error connecting to server")
 	}
@@ -33,7 +33,7 @@ func Connect(host string, port int32) (int32, error) {
 		},
 	)
 
-	slog.Info("Connected to server", "host", host, "port", port, "socketIndex", socketIndex, "Addr", conn.RemoteAddr())
+	logger.Info("Connected to server", "host", host, "port", port, "socketIndex", socketIndex, "Addr", conn.RemoteAddr())
 
 	return socketIndex, nil
 }
@@ -51,7 +51,7 @@ func SendMessage[T constraints.Integer](socketIndex int32, messageId T, proto go
 	socketSubsystem.socketsMutex.RUnlock()
 
 	if !exists {
-		slog.Error("Socket not found", "socketIndex", socketIndex, "messageId", messageId)
+		logger.Error("Socket not found", "socketIndex", socketIndex, "messageId", messageId)
 		return -1, metaerror.New("socket not found: %d", socketIndex)
 	}
 
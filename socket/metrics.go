@@ -0,0 +1,42 @@
+package socket
+
+import (
+	"meta/event"
+	metametrics "meta/meta-metrics"
+	socketEvent "meta/socket/event"
+	"strconv"
+)
+
+var (
+	socketConnections = metametrics.NewGauge(
+		"socket_connections", "Number of open socket connections.",
+	)
+	socketConnectionsRejected = metametrics.NewCounterVec(
+		"socket_connections_rejected_total", "Total number of rejected socket connections.",
+		"reason",
+	)
+	socketPackets = metametrics.NewCounterVec(
+		"socket_packets_total", "Total number of socket packets.",
+		"direction", "message_id",
+	)
+	socketBytes = metametrics.NewCounterVec(
+		"socket_bytes_total", "Total socket payload bytes.",
+		"direction", "message_id",
+	)
+)
+
+func observePacket(direction string, messageId int32, size int) {
+	id := strconv.Itoa(int(messageId))
+	socketPackets.WithLabelValues(direction, id).Inc()
+	socketBytes.WithLabelValues(direction, id).Add(float64(size))
+}
+
+// observeReceivedPacket 消息 ID 来自客户端，只记录注册了处理者的 ID，其余记为 other，避免标签无限增长
+func observeReceivedPacket(messageId int32, size int) {
+	if !event.HasChannelListener[socketEvent.SocketMessage](GetMessageChannelByMessageId(messageId)) {
+		socketPackets.WithLabelValues("receive", "other").Inc()
+		socketBytes.WithLabelValues("receive", "other").Add(float64(size))
+		return
+	}
+	observePacket("receive", messageId, size)
+}
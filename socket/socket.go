@@ -2,17 +2,21 @@ package socket
 
 import (
 	"context"
-	"log/slog"
 	"meta/event"
 	metaerror "meta/meta-error"
+	metatracing "meta/meta-tracing"
 	"meta/metaroutine"
 	"meta/network"
+	"meta/ratelimit"
 	socketEvent "meta/socket/event"
 	socketPayload "meta/socket/payload"
 	"net"
 	"sync"
+	"time"
 
 	googleProto "github.com/golang/protobuf/proto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Socket 封装 net.Conn 和消息通道
@@ -22,6 +26,9 @@ type Socket struct {
 	dataChan       chan []byte
 	sendBuffers    net.Buffers
 	receiveBuffers net.Buffers
+	limiter        *ratelimit.TokenBucket // 接收消息限流，为空则不限制
+	writeMutex     sync.Mutex
+	closeOnce      sync.Once
 }
 
 // NewSocket 创建新的 Socket 实例
@@ -63,7 +70,8 @@ func (s *Socket) Send(messageId int32, proto googleProto.Message) (int32, error)
 			if packageErr != nil {
 				return metaerror.Wrap(packageErr, "error making package")
 			}
-			slog.Info("Message sent", "messageId", messageId, "dataSize", len(networkBytes))
+			logger.Info("Message sent", "messageId", messageId, "dataSize", len(networkBytes))
+			observePacket("send", messageId, len(protoBytes))
 			s.dataChan <- networkBytes
 			return nil
 		},
@@ -105,7 +113,7 @@ func (s *Socket) processSocket(callback func()) error {
 
 	wg.Wait()
 
-	slog.Info("socket End", "index", s.socketIndex)
+	logger.Info("socket End", "index", s.socketIndex)
 
 	callback()
 
@@ -134,6 +142,12 @@ func (s *Socket) handleReceiveMessages(ctx context.Context, wg *sync.WaitGroup)
 				continue
 			}
 			for _, packet := range packets {
+				if s.limiter != nil && !s.limiter.Allow() {
+					logger.Warn("Socket message rate limit exceeded", "socketIndex", s.socketIndex, "Addr", s.conn.RemoteAddr())
+					s.sendCloseReason(CloseReasonMessageRateLimit)
+					return
+				}
+				observeReceivedPacket(packet.MessageId, len(packet.ProtoData))
 				channels := []string{
 					GetMessageChannelBySocketIndex(s.socketIndex),
 					GetMessageChannelByMessageId(packet.MessageId),
@@ -143,7 +157,14 @@ func (s *Socket) handleReceiveMessages(ctx context.Context, wg *sync.WaitGroup)
 					MessageId(packet.MessageId).
 					ProtoByte(packet.ProtoData).
 					Build()
+				_, span := metatracing.StartSpan(
+					ctx, "socket message", trace.SpanKindServer,
+					attribute.Int("socket.index", int(s.socketIndex)),
+					attribute.Int("socket.message_id", int(packet.MessageId)),
+					attribute.Int("socket.message_size", len(packet.ProtoData)),
+				)
 				event.InvokeChannel[socketEvent.SocketMessage](&channels, payload)
+				span.End()
 			}
 		}
 	}
@@ -156,32 +177,65 @@ func (s *Socket) handleSendMessages(ctx context.Context, wg *sync.WaitGroup) {
 		case <-ctx.Done():
 			return
 		default:
+			s.writeMutex.Lock()
 			s.sendBuffers = append(s.sendBuffers, data)
-			if _, err := s.sendBuffers.WriteTo(s.conn); err != nil {
+			_, err := s.sendBuffers.WriteTo(s.conn)
+			s.writeMutex.Unlock()
+			if err != nil {
 				return
 			}
 		}
 	}
 }
 
-// Close 关闭连接和消息通道
+// Close 关闭连接和消息通道，可重复调用
 func (s *Socket) Close() error {
-	defer func() {
-		channels := []string{
-			GetMessageChannelBySocketIndex(s.socketIndex),
-		}
-		payload := socketPayload.NewSocketBuilder().
-			SocketIndex(s.socketIndex).
-			Build()
-		event.InvokeChannel[socketEvent.SocketDisconnected](&channels, payload)
-	}()
-	if s.conn != nil {
-		close(s.dataChan)
+	var err error
+	s.closeOnce.Do(
+		func() {
+			defer func() {
+				channels := []string{
+					GetMessageChannelBySocketIndex(s.socketIndex),
+				}
+				payload := socketPayload.NewSocketBuilder().
+					SocketIndex(s.socketIndex).
+					Build()
+				event.InvokeChannel[socketEvent.SocketDisconnected](&channels, payload)
+			}()
+			if s.conn != nil {
+				close(s.dataChan)
+			}
+			err = s.conn.Close()
+		},
+	)
+	return err
+}
+
+// CloseWithReason 告知客户端断开原因后关闭连接
+func (s *Socket) CloseWithReason(reason CloseReason) error {
+	s.sendCloseReason(reason)
+	return s.Close()
+}
+
+// sendCloseReason 绕过发送队列直接写出断开原因，避免队列阻塞时客户端收不到
+func (s *Socket) sendCloseReason(reason CloseReason) {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	if err := writeCloseReason(s.conn, reason); err != nil {
+		logger.Error("error making close reason package", "socketIndex", s.socketIndex, "err", err)
 	}
-	err := s.conn.Close()
+}
+
+// writeCloseReason 向连接写出断开原因包，最多等待一秒
+func writeCloseReason(conn net.Conn, reason CloseReason) error {
+	reasonBytes := []byte(reason)
+	packet := network.ConvertPacket(nil, -1, -1, CloseMessageId, int32(len(reasonBytes)), reasonBytes)
+	networkBytes, err := network.MakeBytes(packet)
 	if err != nil {
 		return err
 	}
+	_ = conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_, _ = conn.Write(networkBytes)
 	return nil
 }
 
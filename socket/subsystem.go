@@ -1,21 +1,37 @@
 package socket
 
 import (
+	"errors"
 	"fmt"
-	"log/slog"
 	"meta/engine"
 	"meta/generator"
+	metalog "meta/meta-log"
+	"meta/metaroutine"
+	"meta/ratelimit"
 	"meta/subsystem"
 	"net"
 	"sync"
+	"time"
+)
+
+var logger = metalog.GetModuleLogger(metalog.LoggerSocket)
+
+const (
+	acceptBackoffMin        = 5 * time.Millisecond
+	acceptBackoffMaxDefault = time.Second
 )
 
 type Subsystem struct {
 	subsystem.Subsystem
 	GetPort        func() int32
+	GetConfig      func() *Config
+	config         *Config
+	listener       net.Listener
 	indexGenerator *generator.IncreaseGenerator[int32]
 	sockets        map[int32]*Socket
 	socketsMutex   sync.RWMutex
+	acceptedCount  int            // 已接受的连接数，受 socketsMutex 保护
+	ipCounts       map[string]int // 每个 IP 的连接数，受 socketsMutex 保护
 }
 
 func GetSubsystem() *Subsystem {
@@ -31,45 +47,99 @@ func (socketSubsystem *Subsystem) GetName() string {
 
 func (socketSubsystem *Subsystem) Init() error {
 	socketSubsystem.sockets = map[int32]*Socket{}
+	socketSubsystem.ipCounts = map[string]int{}
 	socketSubsystem.indexGenerator = generator.NewIncreaseGenerator[int32](0, 1)
 	return nil
 }
 
 func (socketSubsystem *Subsystem) Start() error {
+	socketSubsystem.config = &Config{}
+	if socketSubsystem.GetConfig != nil {
+		if config := socketSubsystem.GetConfig(); config != nil {
+			socketSubsystem.config = config
+		}
+	}
+	if socketSubsystem.config.AcceptBackoffMax <= 0 {
+		socketSubsystem.config.AcceptBackoffMax = acceptBackoffMaxDefault
+	}
 	if socketSubsystem.GetPort != nil {
 		go socketSubsystem.startSubsystem()
 	}
 	return nil
 }
 
+func (socketSubsystem *Subsystem) Stop() error {
+	socketSubsystem.socketsMutex.Lock()
+	listener := socketSubsystem.listener
+	socketSubsystem.listener = nil
+	sockets := make([]*Socket, 0, len(socketSubsystem.sockets))
+	for _, socket := range socketSubsystem.sockets {
+		sockets = append(sockets, socket)
+	}
+	socketSubsystem.socketsMutex.Unlock()
+	var err error
+	if listener != nil {
+		err = listener.Close()
+	}
+	// 关闭存活连接，收发协程随之退出
+	for _, socket := range sockets {
+		_ = socket.Close()
+	}
+	return err
+}
+
 func (socketSubsystem *Subsystem) startSubsystem() {
 
 	port := socketSubsystem.GetPort()
 
 	socketListener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
-		slog.Error("starting server error", "err", err)
+		logger.Error("starting server error", "err", err)
 		return
 	}
+	socketSubsystem.socketsMutex.Lock()
+	socketSubsystem.listener = socketListener
+	socketSubsystem.socketsMutex.Unlock()
 
 	defer func(listener net.Listener) {
 		err := listener.Close()
-		if err != nil {
-			slog.Error("Error closing listener", "err", err)
+		if err != nil && !errors.Is(err, net.ErrClosed) {
+			logger.Error("Error closing listener", "err", err)
 		}
 	}(socketListener)
 
-	slog.Info("Socket server is listening", "port", port)
+	logger.Info("Socket server is listening", "port", port)
 
+	var backoff time.Duration
 	for {
 		// 接受新的连接
 		conn, err := socketListener.Accept()
 		if err != nil {
-			slog.Info("Error accepting connection:", err)
+			if errors.Is(err, net.ErrClosed) {
+				logger.Info("Socket server stopped", "port", port)
+				return
+			}
+			// 出错时退避，避免文件描述符耗尽等情况下空转
+			backoff = nextAcceptBackoff(backoff, socketSubsystem.config.AcceptBackoffMax)
+			logger.Error("Error accepting connection", "err", err, "backoff", backoff)
+			time.Sleep(backoff)
 			continue
 		}
+		backoff = 0
+
+		ip := getRemoteIp(conn)
+		if reason, ok := socketSubsystem.acquireConnection(ip); !ok {
+			logger.Warn("Connection rejected", "reason", reason, "Addr", conn.RemoteAddr())
+			socketConnectionsRejected.WithLabelValues(string(reason)).Inc()
+			rejectConnection(conn, reason)
+			continue
+		}
+
 		socketIndex := socketSubsystem.indexGenerator.Next() // 获取下一个 ID
 		socket := NewSocket(socketIndex, conn)               // 创建 Socket 实例
+		if socketSubsystem.config.MessageRate > 0 {
+			socket.limiter = ratelimit.NewTokenBucket(socketSubsystem.config.MessageRate, socketSubsystem.config.MessageBurst)
+		}
 		socketSubsystem.socketsMutex.Lock()
 		socketSubsystem.sockets[socketIndex] = socket // 存储 Socket 实例
 		socketSubsystem.socketsMutex.Unlock()
@@ -77,10 +147,74 @@ func (socketSubsystem *Subsystem) startSubsystem() {
 			func() {
 				socketSubsystem.socketsMutex.Lock()
 				delete(socketSubsystem.sockets, socketIndex)
+				socketSubsystem.releaseConnectionUnsafe(ip)
 				socketSubsystem.socketsMutex.Unlock()
 			},
 		)
 
-		slog.Info("New connection", "socketIndex", socketIndex, "Addr", conn.RemoteAddr())
+		logger.Info("New connection", "socketIndex", socketIndex, "Addr", conn.RemoteAddr())
+	}
+}
+
+// acquireConnection 检查连接数限制并占用名额，失败时返回拒绝原因
+func (socketSubsystem *Subsystem) acquireConnection(ip string) (CloseReason, bool) {
+	config := socketSubsystem.config
+	socketSubsystem.socketsMutex.Lock()
+	defer socketSubsystem.socketsMutex.Unlock()
+	if config.MaxConnections > 0 && socketSubsystem.acceptedCount >= config.MaxConnections {
+		return CloseReasonServerFull, false
+	}
+	if config.MaxConnectionsPerIp > 0 && socketSubsystem.ipCounts[ip] >= config.MaxConnectionsPerIp {
+		return CloseReasonIpLimit, false
+	}
+	socketSubsystem.acceptedCount++
+	socketSubsystem.ipCounts[ip]++
+	socketConnections.Inc()
+	return "", true
+}
+
+func (socketSubsystem *Subsystem) releaseConnectionUnsafe(ip string) {
+	socketSubsystem.acceptedCount--
+	socketConnections.Dec()
+	if socketSubsystem.ipCounts[ip] <= 1 {
+		delete(socketSubsystem.ipCounts, ip)
+	} else {
+		socketSubsystem.ipCounts[ip]--
+	}
+}
+
+// rejectConnection 异步发送拒绝原因并关闭，不阻塞 Accept 循环
+func rejectConnection(conn net.Conn, reason CloseReason) {
+	metaroutine.SafeGo(
+		"Socket reject",
+		func() error {
+			if err := writeCloseReason(conn, reason); err != nil {
+				logger.Error("error making close reason package", "err", err)
+			}
+			return conn.Close()
+		},
+	)
+}
+
+func nextAcceptBackoff(backoff time.Duration, maxBackoff time.Duration) time.Duration {
+	if backoff == 0 {
+		backoff = acceptBackoffMin
+	} else {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+func getRemoteIp(conn net.Conn) string {
+	if tcpAddr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		return tcpAddr.IP.String()
+	}
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return conn.RemoteAddr().String()
 	}
+	return host
 }
@@ -0,0 +1,125 @@
+package socket
+
+import (
+	"context"
+	"errors"
+	"io"
+	"meta/network"
+	"meta/ratelimit"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestSubsystem(config *Config) *Subsystem {
+	socketSubsystem := &Subsystem{GetConfig: func() *Config { return config }}
+	_ = socketSubsystem.Init()
+	_ = socketSubsystem.Start()
+	return socketSubsystem
+}
+
+func TestAcquireConnection(t *testing.T) {
+	socketSubsystem := newTestSubsystem(&Config{MaxConnections: 2, MaxConnectionsPerIp: 1})
+	if _, ok := socketSubsystem.acquireConnection("10.0.0.1"); !ok {
+		t.Fatal("Expected first connection to be accepted")
+	}
+	if reason, ok := socketSubsystem.acquireConnection("10.0.0.1"); ok || reason != CloseReasonIpLimit {
+		t.Fatalf("Expected ip limit, got %q", reason)
+	}
+	if _, ok := socketSubsystem.acquireConnection("10.0.0.2"); !ok {
+		t.Fatal("Expected connection from other ip to be accepted")
+	}
+	if reason, ok := socketSubsystem.acquireConnection("10.0.0.3"); ok || reason != CloseReasonServerFull {
+		t.Fatalf("Expected server full, got %q", reason)
+	}
+
+	socketSubsystem.socketsMutex.Lock()
+	socketSubsystem.releaseConnectionUnsafe("10.0.0.1")
+	socketSubsystem.socketsMutex.Unlock()
+	if _, ok := socketSubsystem.ipCounts["10.0.0.1"]; ok {
+		t.Error("Expected released ip to be removed")
+	}
+	if _, ok := socketSubsystem.acquireConnection("10.0.0.1"); !ok {
+		t.Error("Expected released slot to be reusable")
+	}
+	if socketSubsystem.acceptedCount != 2 {
+		t.Errorf("Expected 2 accepted connections, got %d", socketSubsystem.acceptedCount)
+	}
+}
+
+func TestNextAcceptBackoff(t *testing.T) {
+	var backoff time.Duration
+	expected := []time.Duration{5, 10, 20, 40, 50, 50}
+	for i, want := range expected {
+		backoff = nextAcceptBackoff(backoff, 50*time.Millisecond)
+		if backoff != want*time.Millisecond {
+			t.Fatalf("Expected backoff %d to be %v, got %v", i, want*time.Millisecond, backoff)
+		}
+	}
+	// Accept 成功后归零，下次出错重新从最小值开始
+	if backoff = nextAcceptBackoff(0, 50*time.Millisecond); backoff != acceptBackoffMin {
+		t.Errorf("Expected backoff to reset to %v, got %v", acceptBackoffMin, backoff)
+	}
+}
+
+func TestMessageRateLimitCloseReason(t *testing.T) {
+	server, client := net.Pipe()
+	defer func() { _ = client.Close() }()
+	socket := NewSocket(1, server)
+	socket.limiter = ratelimit.NewTokenBucket(0.001, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go socket.handleReceiveMessages(context.Background(), &wg)
+
+	// 突发额度为 1，第二条消息触发限流
+	for i := int16(0); i < 2; i++ {
+		packageBytes, err := network.MakeBytes(network.ConvertPacket(nil, i, -1, 100, 0, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Write(packageBytes); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = client.SetReadDeadline(time.Now().Add(time.Second))
+	closePackage, err := network.LoadPackage(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	received, err := network.ParsePacket(closePackage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 || received[0].MessageId != CloseMessageId ||
+		CloseReason(received[0].ProtoData) != CloseReasonMessageRateLimit {
+		t.Fatalf("Expected rate limit close reason, got %+v", received)
+	}
+	wg.Wait()
+	if _, err := client.Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected connection to be closed, got %v", err)
+	}
+}
+
+func TestStopClosesSockets(t *testing.T) {
+	socketSubsystem := newTestSubsystem(&Config{})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, client := net.Pipe()
+	socketSubsystem.listener = listener
+	socketSubsystem.sockets[1] = NewSocket(1, server)
+
+	if err := socketSubsystem.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := listener.Accept(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Expected listener to be closed, got %v", err)
+	}
+	_ = client.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := client.Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected socket to be closed, got %v", err)
+	}
+}
@@ -2,27 +2,16 @@ package svn
 
 import (
 	"log/slog"
+	metaredact "meta/meta-redact"
 	"os/exec"
-	"slices"
 )
 
 type Cmd struct {
 	exec.Cmd
 }
 
-var excludeArg = []string{"--password"}
-
+// Run 参数中的密码等敏感值在日志中会被替换
 func (c *Cmd) Run() error {
-	var logArg []string
-	for i := 0; i < len(c.Args); i++ {
-		arg := c.Args[i]
-		// 如果包括的跳过参数，那么跳过这个参数和他后面的值
-		if slices.Contains(excludeArg, arg) {
-			i += 1
-			continue
-		}
-		logArg = append(logArg, arg)
-	}
-	slog.Info("command run", "dir", c.Dir, "path", c.Path, "arg", logArg)
+	slog.Info("command run", "dir", c.Dir, "path", c.Path, "arg", metaredact.Args(c.Args))
 	return c.Cmd.Run()
 }
@@ -3,12 +3,13 @@ package svn
 import (
 	"log/slog"
 	"meta/meta-cmd"
+	metaredact "meta/meta-redact"
 	"os/exec"
 	"strings"
 )
 
 func Command(username string, password string, name string, arg ...string) *Cmd {
-	slog.Info("svn command", "cmd", name+" "+strings.Join(arg, " "))
+	slog.Info("svn command", "cmd", name+" "+strings.Join(metaredact.Args(arg), " "))
 	appendArg := []string{"--username", username, "--password", password, "--non-interactive"}
 	svnCmd := &Cmd{*exec.Command(name, append(appendArg, arg...)...)}
 	logReader := &metacmd.LogReader{
@@ -20,7 +21,7 @@ func Command(username string, password string, name string, arg ...string) *Cmd
 }
 
 func CommandWithoutAuth(name string, arg ...string) *Cmd {
-	slog.Info("svn command", "cmd", name+" "+strings.Join(arg, " "))
+	slog.Info("svn command", "cmd", name+" "+strings.Join(metaredact.Args(arg), " "))
 	appendArg := []string{"--non-interactive"}
 	svnCmd := &Cmd{*exec.Command(name, append(appendArg, arg...)...)}
 	logReader := &metacmd.LogReader{